@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"github.com/chrismeh/lefty/internal/inmem"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	archiveDir := flag.String("archive", "", "directory containing archived pages")
+	productsFile := flag.String("products", "products.json", "products.json to update with the reparsed products")
+	flag.Parse()
+
+	if *archiveDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	archive, err := retailer.OpenArchive(*archiveDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Loaded %d archived pages", archive.Len())
+
+	store := inmem.NewProductStore()
+	f, err := os.Open(*productsFile)
+	if err == nil {
+		err = store.Load(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("could not load %s: %s", *productsFile, err)
+		}
+		count, err := store.Count(retailer.Filter{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Loaded %d products from %s", count, *productsFile)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Fatal(err)
+	}
+
+	for _, info := range retailer.BuiltinRetailers() {
+		r, err := retailer.NewBuiltinRetailer(info.Name, archive)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		prds, err := retailer.LoadProducts(r)
+		if err != nil {
+			log.Printf("Skipped %s: %s", r.Name(), err)
+			continue
+		}
+
+		if err = store.Upsert(prds); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Parsed %d products of %s", len(prds), r.Name())
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(*productsFile), filepath.Base(*productsFile)+".tmp")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	err = store.Dump(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), *productsFile)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"log"
+	"net/http"
+	"os"
+)
+
+func main() {
+	definition := flag.String("definition", "", "path to the retailer definition")
+	fixture := flag.String("fixture", "", "path to a saved listing page")
+	page := flag.Uint("page", 1, "page number of the saved listing page")
+	flag.Parse()
+
+	if *definition == "" || *fixture == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*definition)
+	if err != nil {
+		log.Fatal(err)
+	}
+	d, err := retailer.LoadDefinition(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r, err := retailer.NewConfiguredRetailer(fixtureGetter{path: *fixture}, d)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := r.LoadProducts(d.Categories[0], retailer.RequestOptions{Page: *page})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	problems := 0
+	for _, p := range resp.Products {
+		fmt.Printf("%s | %.2f %s | %s | %s\n", p, p.Price, p.Currency, p.AvailabilityInfo, p.ProductURL)
+
+		for _, problem := range validateProduct(p) {
+			fmt.Printf("\t%s\n", problem)
+			problems++
+		}
+	}
+	fmt.Printf("%d products on page %d of %d, %d problems\n", len(resp.Products), resp.CurrentPage, resp.LastPage, problems)
+
+	if len(resp.Products) == 0 || problems > 0 {
+		os.Exit(1)
+	}
+}
+
+func validateProduct(p retailer.Product) []string {
+	problems := make([]string, 0)
+	if p.Manufacturer == "" {
+		problems = append(problems, "missing manufacturer")
+	}
+	if p.Price == 0 {
+		problems = append(problems, "missing price")
+	}
+	if p.ProductURL == "" {
+		problems = append(problems, "missing product url")
+	}
+	if p.ThumbnailURL == "" {
+		problems = append(problems, "missing thumbnail url")
+	}
+	if p.Category == "" {
+		problems = append(problems, "missing category")
+	}
+
+	return problems
+}
+
+type fixtureGetter struct {
+	path string
+}
+
+func (f fixtureGetter) Get(url string) (*http.Response, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: file}, nil
+}
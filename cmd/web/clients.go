@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"log"
+	"net/http"
+	"time"
+)
+
+type httpGetter interface {
+	Get(url string) (*http.Response, error)
+}
+
+type httpClients struct {
+	errorLog   *log.Logger
+	config     retailer.HTTPConfig
+	cacheDir   string
+	archiveDir string
+	caches     map[string]*retailer.CachingClient
+}
+
+func (h *httpClients) For(name string) httpGetter {
+	var c httpGetter
+	client, err := h.config.Settings(name).NewClient()
+	if err != nil {
+		h.errorLog.Printf("invalid http settings for %s, using defaults: %s", name, err)
+		c = &http.Client{Timeout: 5 * time.Second}
+	} else {
+		c = client
+	}
+
+	if h.cacheDir != "" && client != nil {
+		cache, err := retailer.NewCachingClient(client, h.cacheDir)
+		if err != nil {
+			h.errorLog.Println(err)
+		} else {
+			h.caches[name] = cache
+			c = cache
+		}
+	}
+	if h.archiveDir != "" {
+		ac, err := retailer.NewArchivingClient(c, h.archiveDir)
+		if err != nil {
+			h.errorLog.Println(err)
+		} else {
+			c = ac
+		}
+	}
+
+	return c
+}
+
+func (h *httpClients) Cache(name string) *retailer.CachingClient {
+	return h.caches[name]
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"crypto/subtle"
+	"errors"
 	"github.com/chrismeh/lefty/pkg/retailer"
 	"io"
-	"math"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 )
 
 func (a application) handleShowIndex(w http.ResponseWriter, _ *http.Request) {
@@ -47,22 +50,23 @@ func (a application) handleGetProducts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	prds, err := a.productStore.FindAll(filter)
+	result, err := a.productStore.Query(filter)
+	if errors.Is(err, retailer.ErrPageOutOfRange) {
+		a.jsonError(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		a.jsonError(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	count := a.productStore.Count(filter)
-	lastPage := math.Ceil(float64(count) / float64(filter.ProductsPerPage))
-
 	resp := response{
-		Data: prds,
+		Data: result.Products,
 		Meta: meta{
-			CurrentPage:  filter.Page,
-			LastPage:     uint(lastPage),
-			OverallCount: uint(count),
-			Count:        uint(len(prds)),
+			CurrentPage:  result.Page,
+			LastPage:     result.LastPage,
+			OverallCount: result.Total,
+			Count:        uint(len(result.Products)),
 		},
 	}
 	err = a.json(w, resp)
@@ -72,6 +76,94 @@ func (a application) handleGetProducts(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (a application) handleGetRetailers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		a.jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := a.json(w, retailersResponse{Data: a.registry.Infos()})
+	if err != nil {
+		a.jsonError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (a application) handleGetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		a.jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := statusResponse{
+		Retailers: a.healthMonitor.Reports(),
+		Alerts:    a.healthMonitor.Alerts(),
+	}
+	err := a.json(w, resp)
+	if err != nil {
+		a.jsonError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (a application) handleConfirmAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := a.healthMonitor.Confirm(a.productStore, r.URL.Query().Get("retailer"))
+	if err != nil {
+		a.jsonError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	a.dumpHealth(filepath.Join(a.dataDir, "health.json"))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a application) handleDismissAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := a.healthMonitor.Dismiss(r.URL.Query().Get("retailer"))
+	if err != nil {
+		a.jsonError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	a.dumpHealth(filepath.Join(a.dataDir, "health.json"))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a application) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.adminToken == "" {
+			a.jsonError(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) != 1 {
+			a.jsonError(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+type retailersResponse struct {
+	Data []retailer.RetailerInfo `json:"data"`
+}
+
+type statusResponse struct {
+	Retailers []retailer.CrawlReport    `json:"retailers"`
+	Alerts    []retailer.StructureAlert `json:"alerts"`
+}
+
 type response struct {
 	Data []retailer.Product `json:"data"`
 	Meta meta               `json:"meta"`
@@ -7,18 +7,56 @@ let app = new Vue({
         "search": "",
         "retailer": "",
         "requestedPage": 1,
+        "status": [],
+        "retailers": [],
+    },
+    computed: {
+        enabledRetailers: function() {
+            return this.retailers.filter(r => r.enabled);
+        },
     },
     methods: {
         fetchProducts: async function () {
             const response = await fetch(this.buildApiUrl());
+            if (response.status === 404 && this.requestedPage > 1) {
+                this.requestedPage = 1;
+                return;
+            }
             const json = await response.json();
             this.products = json.data;
             this.pagination = json.meta;
         },
+        fetchRetailers: async function () {
+            const response = await fetch('/api/retailers');
+            const json = await response.json();
+            this.retailers = json.data;
+        },
+        fetchStatus: async function () {
+            const response = await fetch('/api/status');
+            const json = await response.json();
+            this.status = json.retailers;
+        },
+        healthClass: function(report) {
+            if (report.breaker !== "closed") {
+                return "is-danger";
+            }
+            return report.health_score >= 80 ? "is-success" : "is-warning";
+        },
+        showFirstPage: async function() {
+            if (this.requestedPage !== 1) {
+                this.requestedPage = 1;
+                return;
+            }
+            await this.fetchProducts();
+        },
         resetSearchTerm: async function() {
             this.search = "";
             await this.fetchProducts();
         },
+        currencySymbol: function(currency) {
+            const symbols = {"": "€", "EUR": "€", "GBP": "£", "CZK": "Kč", "PLN": "zł"};
+            return currency in symbols ? symbols[currency] : currency;
+        },
         buildApiUrl: function() {
             let url = `/api/products?order=${this.order}`
             let search = this.search.trim()
@@ -38,20 +76,22 @@ let app = new Vue({
     },
     watch: {
         order: async function() {
-            await this.fetchProducts();
+            await this.showFirstPage();
         },
         retailer: async function() {
-            await this.fetchProducts();
+            await this.showFirstPage();
         },
         search: debounce(async function() {
-            await this.fetchProducts();
+            await this.showFirstPage();
         }, 500),
         requestedPage: async function() {
             await this.fetchProducts();
         }
     },
     mounted: async function () {
+       await this.fetchRetailers();
        await this.fetchProducts();
+       await this.fetchStatus();
     },
 })
 
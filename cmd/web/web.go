@@ -5,33 +5,120 @@ import (
 	"errors"
 	"flag"
 	"github.com/chrismeh/lefty/internal/inmem"
+	"github.com/chrismeh/lefty/internal/sqlite"
 	"github.com/chrismeh/lefty/pkg/retailer"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 )
 
 type application struct {
-	infoLog      *log.Logger
-	errorLog     *log.Logger
-	productStore *inmem.ProductStore
+	infoLog        *log.Logger
+	errorLog       *log.Logger
+	productStore   retailer.ProductRepository
+	definitionsDir string
+	shopifyFile    string
+	feedsFile      string
+	discover       bool
+	archiveDir     string
+	healthMonitor  *retailer.HealthMonitor
+	dataDir        string
+	checkpointAge  time.Duration
+	cacheDir       string
+	httpConfigFile string
+	retailersFile  string
+	persist        bool
+	adminToken     string
+	clients        *httpClients
+	registry       *retailer.Registry
+}
+
+type productSnapshotter interface {
+	Dump(w io.Writer) error
+	Load(r io.Reader) error
+}
+
+type productSnapshotTaker interface {
+	Snapshot() error
+}
+
+type productImporter interface {
+	Import(r io.Reader) error
 }
 
 func main() {
 	addr := flag.String("port", ":5000", "HTTP address to listen on")
+	definitionsDir := flag.String("definitions", "", "directory containing retailer definitions")
+	shopifyFile := flag.String("shopify", "", "file containing one Shopify shop configuration per line")
+	feedsFile := flag.String("feeds", "", "file containing one product feed configuration per line")
+	discover := flag.Bool("discover", false, "crawl newly discovered left-handed categories")
+	archiveDir := flag.String("archive", "", "directory to archive fetched pages in")
+	dataDir := flag.String("data", ".", "directory to store products and crawl checkpoints in")
+	checkpointAge := flag.Duration("checkpoint-max-age", 24*time.Hour, "maximum age of a crawl checkpoint to resume from")
+	cacheDir := flag.String("cache", "", "directory to cache fetched pages in for conditional requests")
+	httpConfigFile := flag.String("http-config", "", "file containing http settings per retailer")
+	retailersFile := flag.String("retailers", "", "file enabling, disabling and describing retailers")
+	database := flag.String("db", "", "SQLite database to store products in instead of memory")
+	persist := flag.Bool("persist", false, "log every product update to the data directory and recover it on startup")
+	snapshotInterval := flag.Duration("snapshot-interval", time.Hour, "interval between product snapshots when persisting")
+	snapshotGzip := flag.Bool("snapshot-gzip", false, "compress product snapshots with gzip")
+	adminToken := flag.String("admin-token", "", "bearer token required to confirm or dismiss structure alerts")
 	flag.Parse()
 
+	if *persist && *database != "" {
+		log.Fatal("-persist cannot be combined with -db")
+	}
+
+	var productStore retailer.ProductRepository = inmem.NewProductStore()
+	if *persist {
+		store, err := inmem.OpenProductStore(*dataDir, inmem.PersistOptions{Compress: *snapshotGzip})
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer store.Close()
+		productStore = store
+	}
+	if *database != "" {
+		db, err := sqlite.Open(*database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+		productStore = db
+	}
 	app := application{
-		infoLog:      log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime),
-		errorLog:     log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile),
-		productStore: inmem.NewProductStore(),
+		infoLog:        log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime),
+		errorLog:       log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile),
+		productStore:   productStore,
+		definitionsDir: *definitionsDir,
+		shopifyFile:    *shopifyFile,
+		feedsFile:      *feedsFile,
+		discover:       *discover,
+		archiveDir:     *archiveDir,
+		healthMonitor:  retailer.NewHealthMonitor(retailer.DefaultHealthThresholds),
+		dataDir:        *dataDir,
+		checkpointAge:  *checkpointAge,
+		cacheDir:       *cacheDir,
+		httpConfigFile: *httpConfigFile,
+		retailersFile:  *retailersFile,
+		persist:        *persist,
+		adminToken:     *adminToken,
 	}
+	app.clients = app.httpClients()
+	app.registry = app.newRegistry()
 
 	router := http.NewServeMux()
 	router.Handle("/static/", http.StripPrefix("/static", http.FileServer(http.Dir("./static"))))
 	router.HandleFunc("/", app.handleShowIndex)
 	router.HandleFunc("/api/products", app.handleGetProducts)
+	router.HandleFunc("/api/retailers", app.handleGetRetailers)
+	router.HandleFunc("/api/status", app.handleGetStatus)
+	router.HandleFunc("/api/alerts/confirm", app.requireAdmin(app.handleConfirmAlert))
+	router.HandleFunc("/api/alerts/dismiss", app.requireAdmin(app.handleDismissAlert))
 
 	s := &http.Server{
 		Addr:         *addr,
@@ -42,6 +129,9 @@ func main() {
 	}
 
 	go app.updateRetailers()
+	if s, ok := productStore.(productSnapshotTaker); ok && *persist {
+		go app.snapshotProducts(s, *snapshotInterval)
+	}
 
 	app.infoLog.Printf("starting application at %s", s.Addr)
 	err := s.ListenAndServe()
@@ -63,35 +153,383 @@ func (a application) jsonError(w http.ResponseWriter, error string, code int) {
 }
 
 func (a application) updateRetailers() {
-	f, err := os.Open("products.json")
-	if err == nil {
+	productsFile := filepath.Join(a.dataDir, "products.json")
+	if a.loadProducts(productsFile) {
 		a.infoLog.Println("Skipped retailer update: products.json found")
-		_ = a.productStore.Load(f)
-		f.Close()
 		return
 	}
-	if !errors.Is(err, os.ErrNotExist) {
-		panic(err)
-	}
-	defer f.Close()
 
 	start := time.Now()
 	a.infoLog.Println("Starting retailer update ...")
 
-	c := http.Client{Timeout: 5 * time.Second}
-	err = retailer.UpdateRetailers(a.productStore, retailer.NewThomann(&c), retailer.NewMusikProduktiv(&c))
+	healthFile := filepath.Join(a.dataDir, "health.json")
+	a.loadHealth(healthFile)
+
+	retailers := a.registry.Retailers()
+
+	checkpointFile := filepath.Join(a.dataDir, "crawl_checkpoint.json")
+	checkpoint, err := retailer.OpenCheckpoint(checkpointFile, a.checkpointAge)
+	if err != nil {
+		a.errorLog.Printf("Discarding crawl checkpoint: %s", err)
+		if err = os.Remove(checkpointFile); err != nil {
+			a.errorLog.Println(err)
+		}
+		if checkpoint, err = retailer.OpenCheckpoint(checkpointFile, a.checkpointAge); err != nil {
+			a.errorLog.Println(err)
+			return
+		}
+	}
+	if checkpoint.Len() > 0 {
+		a.infoLog.Printf("Resuming crawl started at %s, %d pages already done", checkpoint.StartedAt().Format(time.RFC3339), checkpoint.Len())
+	}
+
+	crawl := a.discoverCategories(retailers)
+	for i, r := range crawl {
+		if cache := a.clients.Cache(r.Name()); cache != nil {
+			r = retailer.WithCache(r, cache)
+		}
+		crawl[i] = retailer.WithCheckpoint(r, checkpoint)
+	}
+
+	err = retailer.UpdateRetailersWithHealthCheck(a.productStore, a.healthMonitor, crawl...)
 	if err != nil {
 		a.errorLog.Println(err)
 	}
+	failed := err != nil
+	healthy := make(map[string]bool)
+	for _, report := range a.healthMonitor.Reports() {
+		healthy[report.Retailer] = report.Status == retailer.CrawlStatusOK
+		if report.Status == retailer.CrawlStatusFailed {
+			a.errorLog.Printf("Crawling %s failed: %s", report.Retailer, report.Error)
+			failed = true
+		}
+		if report.Status == retailer.CrawlStatusSkipped {
+			a.infoLog.Printf("Skipped %s, circuit breaker is %s", report.Retailer, report.Breaker)
+		}
+	}
+	if !failed {
+		if err = checkpoint.Remove(); err != nil {
+			a.errorLog.Println(err)
+		}
+	}
+	for _, alert := range a.healthMonitor.Alerts() {
+		a.errorLog.Printf("Structure of %s changed, kept previous products: %v", alert.Retailer, alert.Reasons)
+	}
+
+	a.dumpHealth(healthFile)
 
 	duration := time.Since(start)
 	a.infoLog.Printf("Finished retailer update after %d ms", duration.Milliseconds())
 
-	f, err = os.Create("products.json")
+	for _, r := range retailers {
+		if !healthy[r.Name()] {
+			a.infoLog.Printf("Skipped loading details of %s, its last crawl was not healthy", r.Name())
+			continue
+		}
+		err = retailer.UpdateDetails(a.productStore, a.productStore, r, retailer.DetailOptions{Budget: 50, MaxAge: 7 * 24 * time.Hour})
+		if err != nil {
+			a.errorLog.Println(err)
+		}
+	}
+
+	if s, ok := a.productStore.(productSnapshotTaker); ok && a.persist {
+		if err = s.Snapshot(); err != nil {
+			a.errorLog.Println(err)
+		}
+		return
+	}
+	if s, ok := a.productStore.(productSnapshotter); ok {
+		a.dumpProducts(s, productsFile)
+	}
+}
+
+func (a application) dumpProducts(s productSnapshotter, path string) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		a.errorLog.Println(err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	err = s.Dump(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		a.errorLog.Printf("could not store products: %s", err)
+	}
+}
+
+func (a application) snapshotProducts(s productSnapshotTaker, interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := s.Snapshot(); err != nil {
+			a.errorLog.Println(err)
+		}
+	}
+}
+
+func (a application) loadProducts(path string) bool {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
 	if err != nil {
 		panic(err)
 	}
+	defer f.Close()
+
+	if s, ok := a.productStore.(productSnapshotter); ok && !a.persist {
+		if err = s.Load(f); err != nil {
+			a.errorLog.Printf("could not load products.json, crawling instead: %s", err)
+			return false
+		}
+		return true
+	}
+	if s, ok := a.productStore.(productImporter); ok {
+		count, err := a.productStore.Count(retailer.Filter{})
+		if err != nil {
+			a.errorLog.Printf("could not import products.json: %s", err)
+			return false
+		}
+		if count > 0 {
+			return false
+		}
+		if err = s.Import(f); err != nil {
+			a.errorLog.Printf("could not import products.json: %s", err)
+			return false
+		}
+		if count, err = a.productStore.Count(retailer.Filter{}); err != nil {
+			a.errorLog.Println(err)
+			return false
+		}
+		a.infoLog.Printf("Imported %d products from products.json", count)
+	}
+
+	return false
+}
+
+func (a application) newRegistry() *retailer.Registry {
+	var config retailer.RegistryConfig
+	if a.retailersFile != "" {
+		f, err := os.Open(a.retailersFile)
+		if err != nil {
+			a.errorLog.Println(err)
+		} else {
+			config, err = retailer.LoadRegistryConfig(f)
+			f.Close()
+			if err != nil {
+				a.errorLog.Println(err)
+			}
+		}
+	}
+
+	registry := retailer.NewRegistry(config)
+	for _, info := range retailer.BuiltinRetailers() {
+		r, err := retailer.NewBuiltinRetailer(info.Name, a.clients.For(info.Name))
+		if err != nil {
+			a.errorLog.Println(err)
+			continue
+		}
+		a.register(registry, info, r)
+	}
+	for _, r := range a.configuredRetailers(a.clients) {
+		a.register(registry, retailer.RetailerInfo{}, r)
+	}
+	for _, r := range a.shopifyRetailers(a.clients) {
+		a.register(registry, retailer.RetailerInfo{}, r)
+	}
+	for _, r := range a.feedRetailers(a.clients) {
+		a.register(registry, retailer.RetailerInfo{}, r)
+	}
+
+	return registry
+}
+
+func (a application) register(registry *retailer.Registry, info retailer.RetailerInfo, r retailer.Retailer) {
+	if err := registry.Register(info, r); err != nil {
+		a.errorLog.Printf("skipped retailer: %s", err)
+	}
+}
+
+func (a application) loadHealth(path string) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		a.errorLog.Println(err)
+		return
+	}
+	defer f.Close()
+
+	if err = a.healthMonitor.Load(f); err != nil {
+		a.errorLog.Printf("could not load crawl health: %s", err)
+	}
+}
+
+func (a application) dumpHealth(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		a.errorLog.Println(err)
+		return
+	}
+	defer f.Close()
+
+	if err = a.healthMonitor.Dump(f); err != nil {
+		a.errorLog.Printf("could not store crawl health: %s", err)
+	}
+}
+
+func (a application) configuredRetailers(clients *httpClients) []retailer.Retailer {
+	if a.definitionsDir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(a.definitionsDir, "*.json"))
+	if err != nil {
+		a.errorLog.Println(err)
+		return nil
+	}
+
+	retailers := make([]retailer.Retailer, 0, len(files))
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			a.errorLog.Println(err)
+			continue
+		}
+
+		d, err := retailer.LoadDefinition(f)
+		f.Close()
+		if err != nil {
+			a.errorLog.Printf("skipped retailer definition %s: %s", file, err)
+			continue
+		}
+
+		r, err := retailer.NewConfiguredRetailer(clients.For(d.Name), d)
+		if err != nil {
+			a.errorLog.Printf("skipped retailer definition %s: %s", file, err)
+			continue
+		}
+
+		retailers = append(retailers, r)
+	}
+
+	return retailers
+}
+
+func (a application) shopifyRetailers(clients *httpClients) []retailer.Retailer {
+	if a.shopifyFile == "" {
+		return nil
+	}
+
+	f, err := os.Open(a.shopifyFile)
+	if err != nil {
+		a.errorLog.Println(err)
+		return nil
+	}
+	defer f.Close()
+
+	configs, err := retailer.LoadShopifyConfigs(f)
+	if err != nil {
+		a.errorLog.Println(err)
+		return nil
+	}
+
+	retailers := make([]retailer.Retailer, 0, len(configs))
+	for _, config := range configs {
+		r, err := retailer.NewShopifyRetailer(clients.For(config.Name), config)
+		if err != nil {
+			a.errorLog.Printf("skipped Shopify shop %s: %s", config.Name, err)
+			continue
+		}
+
+		retailers = append(retailers, r)
+	}
+
+	return retailers
+}
+
+func (a application) feedRetailers(clients *httpClients) []retailer.Retailer {
+	if a.feedsFile == "" {
+		return nil
+	}
+
+	f, err := os.Open(a.feedsFile)
+	if err != nil {
+		a.errorLog.Println(err)
+		return nil
+	}
+	defer f.Close()
+
+	configs, err := retailer.LoadFeedConfigs(f)
+	if err != nil {
+		a.errorLog.Println(err)
+		return nil
+	}
+
+	retailers := make([]retailer.Retailer, 0, len(configs))
+	for _, config := range configs {
+		r, err := retailer.NewFeedRetailer(clients.For(config.Name), config)
+		if err != nil {
+			a.errorLog.Printf("skipped product feed %s: %s", config.Name, err)
+			continue
+		}
+
+		retailers = append(retailers, r)
+	}
+
+	return retailers
+}
+
+func (a application) discoverCategories(retailers []retailer.Retailer) []retailer.Retailer {
+	crawl := make([]retailer.Retailer, len(retailers))
+	for i, r := range retailers {
+		crawl[i] = r
+
+		diff, err := retailer.DiscoverCategories(r)
+		if err != nil {
+			a.errorLog.Println(err)
+			continue
+		}
+		if diff.IsEmpty() {
+			continue
+		}
+
+		a.infoLog.Printf("Categories of %s changed, added: %v, missing: %v", r.Name(), diff.Added, diff.Missing)
+		if a.discover {
+			crawl[i] = retailer.WithAdditionalCategories(r, diff.Added)
+		}
+	}
+
+	return crawl
+}
+
+func (a application) httpClients() *httpClients {
+	clients := &httpClients{
+		errorLog:   a.errorLog,
+		cacheDir:   a.cacheDir,
+		archiveDir: a.archiveDir,
+		caches:     make(map[string]*retailer.CachingClient),
+	}
+	if a.httpConfigFile == "" {
+		return clients
+	}
+
+	f, err := os.Open(a.httpConfigFile)
+	if err != nil {
+		a.errorLog.Println(err)
+		return clients
+	}
+	defer f.Close()
+
+	clients.config, err = retailer.LoadHTTPConfig(f)
+	if err != nil {
+		a.errorLog.Println(err)
+	}
 
-	_ = a.productStore.Dump(f)
-	f.Close()
+	return clients
 }
@@ -0,0 +1,153 @@
+package inmem
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"sort"
+)
+
+type catalog struct {
+	products       map[string]retailer.Product
+	history        map[string][]retailer.PricePoint
+	byPrice        []catalogEntry
+	byAvailability []catalogEntry
+	index          *searchIndex
+}
+
+type catalogEntry struct {
+	id      string
+	product retailer.Product
+}
+
+func newCatalog(products map[string]retailer.Product, history map[string][]retailer.PricePoint) *catalog {
+	return buildCatalog(products, history, newSearchIndex(products))
+}
+
+func (c *catalog) next(products map[string]retailer.Product, history map[string][]retailer.PricePoint, changed []string) *catalog {
+	return buildCatalog(products, history, c.index.update(products, changed))
+}
+
+func buildCatalog(products map[string]retailer.Product, history map[string][]retailer.PricePoint, index *searchIndex) *catalog {
+	entries := make([]catalogEntry, 0, len(products))
+	for id, p := range products {
+		entries = append(entries, catalogEntry{id: id, product: p})
+	}
+
+	byPrice := make([]catalogEntry, len(entries))
+	copy(byPrice, entries)
+	sort.Slice(byPrice, func(i, j int) bool {
+		if byPrice[i].product.Price != byPrice[j].product.Price {
+			return byPrice[i].product.Price < byPrice[j].product.Price
+		}
+		return byPrice[i].id < byPrice[j].id
+	})
+
+	byAvailability := entries
+	sort.Slice(byAvailability, func(i, j int) bool {
+		if byAvailability[i].product.AvailabilityScore != byAvailability[j].product.AvailabilityScore {
+			return byAvailability[i].product.AvailabilityScore < byAvailability[j].product.AvailabilityScore
+		}
+		return byAvailability[i].id < byAvailability[j].id
+	})
+
+	return &catalog{products: products, history: history, byPrice: byPrice, byAvailability: byAvailability, index: index}
+}
+
+func (c *catalog) sorted(order string) ([]catalogEntry, bool) {
+	switch order {
+	case retailer.OrderPriceDesc:
+		return c.byPrice, true
+	case retailer.OrderByAvailabilityAsc:
+		return c.byAvailability, false
+	case retailer.OrderByAvailabilityDesc:
+		return c.byAvailability, true
+	default:
+		return c.byPrice, false
+	}
+}
+
+func (c *catalog) query(f retailer.Filter) (retailer.QueryResult, error) {
+	entries, desc := c.sorted(f.OrderBy)
+	at := func(i int) *catalogEntry {
+		if desc {
+			return &entries[len(entries)-1-i]
+		}
+		return &entries[i]
+	}
+
+	var matches []int
+	total := uint(len(entries))
+	if f.HasFilterCriteria() {
+		matches = c.match(f, at)
+		total = uint(len(matches))
+	}
+
+	w, err := f.Window(total)
+	if err != nil {
+		return retailer.QueryResult{}, err
+	}
+
+	prds := make([]retailer.Product, 0, w.Limit)
+	for i := w.Offset; i < w.Offset+w.Limit; i++ {
+		if matches != nil {
+			prds = append(prds, at(matches[i]).product)
+		} else {
+			prds = append(prds, at(int(i)).product)
+		}
+	}
+
+	return retailer.NewQueryResult(prds, total, w), nil
+}
+
+func (c *catalog) match(f retailer.Filter, at func(i int) *catalogEntry) []int {
+	scores, searching := c.index.search(f.Search)
+	matches := make([]int, 0)
+	for i := range c.byPrice {
+		e := at(i)
+		if f.Retailer != "" && f.Retailer != e.product.Retailer {
+			continue
+		}
+		if _, ok := scores[e.id]; searching && !ok {
+			continue
+		}
+		matches = append(matches, i)
+	}
+
+	if searching && f.OrderBy == retailer.OrderByRelevance {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return scores[at(matches[i]).id] > scores[at(matches[j]).id]
+		})
+	}
+
+	return matches
+}
+
+func (c *catalog) count(f retailer.Filter) int {
+	if !f.HasFilterCriteria() {
+		return len(c.products)
+	}
+
+	f.OrderBy = ""
+	return len(c.match(f, func(i int) *catalogEntry { return &c.byPrice[i] }))
+}
+
+func (c *catalog) clone() (map[string]retailer.Product, map[string][]retailer.PricePoint) {
+	products := make(map[string]retailer.Product, len(c.products))
+	for id, p := range c.products {
+		products[id] = p
+	}
+	history := make(map[string][]retailer.PricePoint, len(c.history))
+	for id, h := range c.history {
+		history[id] = h
+	}
+
+	return products, history
+}
+
+func record(history map[string][]retailer.PricePoint, id string, point retailer.PricePoint) {
+	points := history[id]
+	if len(points) > 0 && !points[len(points)-1].Differs(point) {
+		return
+	}
+
+	history[id] = append(points[:len(points):len(points)], point)
+}
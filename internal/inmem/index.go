@@ -0,0 +1,218 @@
+package inmem
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	weightName  = 2
+	weightOther = 1
+)
+
+type searchIndex struct {
+	postings    map[string]map[string]float64
+	docs        map[string]map[string]float64
+	lengths     map[string]float64
+	totalLength float64
+	terms       []string
+}
+
+func newSearchIndex(products map[string]retailer.Product) *searchIndex {
+	idx := &searchIndex{
+		postings: make(map[string]map[string]float64),
+		docs:     make(map[string]map[string]float64, len(products)),
+		lengths:  make(map[string]float64, len(products)),
+	}
+	for id, p := range products {
+		idx.add(id, p, nil)
+	}
+	idx.sortTerms()
+
+	return idx
+}
+
+func (idx *searchIndex) update(products map[string]retailer.Product, changed []string) *searchIndex {
+	if len(changed) > len(products)/4 {
+		return newSearchIndex(products)
+	}
+
+	next := &searchIndex{
+		postings:    make(map[string]map[string]float64, len(idx.postings)),
+		docs:        make(map[string]map[string]float64, len(idx.docs)),
+		lengths:     make(map[string]float64, len(idx.lengths)),
+		totalLength: idx.totalLength,
+		terms:       idx.terms,
+	}
+	for term, postings := range idx.postings {
+		next.postings[term] = postings
+	}
+	for id, doc := range idx.docs {
+		next.docs[id] = doc
+	}
+	for id, length := range idx.lengths {
+		next.lengths[id] = length
+	}
+
+	copied := make(map[string]bool)
+	termsChanged := false
+	for _, id := range changed {
+		if next.remove(id, copied) {
+			termsChanged = true
+		}
+		if p, ok := products[id]; ok {
+			if next.add(id, p, copied) {
+				termsChanged = true
+			}
+		}
+	}
+	if termsChanged {
+		next.sortTerms()
+	}
+
+	return next
+}
+
+func (idx *searchIndex) add(id string, p retailer.Product, copied map[string]bool) bool {
+	doc := make(map[string]float64)
+	for _, term := range retailer.Tokenize(p.String()) {
+		doc[term] += weightName
+	}
+	other := []string{p.Category, p.Condition}
+	for _, value := range p.Specs {
+		other = append(other, value)
+	}
+	for _, term := range retailer.Tokenize(strings.Join(other, " ")) {
+		doc[term] += weightOther
+	}
+
+	var length float64
+	newTerms := false
+	for term, tf := range doc {
+		length += tf
+		postings, ok := idx.postings[term]
+		if !ok {
+			postings = make(map[string]float64)
+			idx.postings[term] = postings
+			newTerms = true
+		} else if copied != nil && !copied[term] {
+			postings = copyPostings(postings)
+			idx.postings[term] = postings
+		}
+		if copied != nil {
+			copied[term] = true
+		}
+		postings[id] = tf
+	}
+	idx.docs[id] = doc
+	idx.lengths[id] = length
+	idx.totalLength += length
+
+	return newTerms
+}
+
+func (idx *searchIndex) remove(id string, copied map[string]bool) bool {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+
+	removedTerms := false
+	for term := range doc {
+		postings := idx.postings[term]
+		if len(postings) == 1 {
+			delete(idx.postings, term)
+			removedTerms = true
+			continue
+		}
+		if !copied[term] {
+			postings = copyPostings(postings)
+			idx.postings[term] = postings
+			copied[term] = true
+		}
+		delete(postings, id)
+	}
+	idx.totalLength -= idx.lengths[id]
+	delete(idx.docs, id)
+	delete(idx.lengths, id)
+
+	return removedTerms
+}
+
+func (idx *searchIndex) sortTerms() {
+	terms := make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	idx.terms = terms
+}
+
+func (idx *searchIndex) expand(prefix string) []string {
+	start := sort.SearchStrings(idx.terms, prefix)
+	end := start
+	for end < len(idx.terms) && strings.HasPrefix(idx.terms[end], prefix) {
+		end++
+	}
+
+	return idx.terms[start:end]
+}
+
+func (idx *searchIndex) search(query string) (map[string]float64, bool) {
+	terms := retailer.Tokenize(query)
+	if len(terms) == 0 {
+		return nil, false
+	}
+
+	n := float64(len(idx.docs))
+	avgLength := idx.totalLength / math.Max(n, 1)
+
+	var scores map[string]float64
+	for _, term := range terms {
+		best := make(map[string]float64)
+		for _, t := range idx.expand(term) {
+			postings := idx.postings[t]
+			df := float64(len(postings))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for id, tf := range postings {
+				if scores != nil {
+					if _, ok := scores[id]; !ok {
+						continue
+					}
+				}
+				norm := tf + bm25K1*(1-bm25B+bm25B*idx.lengths[id]/avgLength)
+				if s := idf * tf * (bm25K1 + 1) / norm; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = best
+			continue
+		}
+		for id := range scores {
+			if s, ok := best[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	return scores, true
+}
+
+func copyPostings(postings map[string]float64) map[string]float64 {
+	c := make(map[string]float64, len(postings)+1)
+	for id, tf := range postings {
+		c[id] = tf
+	}
+
+	return c
+}
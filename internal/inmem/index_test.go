@@ -0,0 +1,82 @@
+package inmem
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProductStore_Search(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sort by relevance", func(t *testing.T) {
+		t.Parallel()
+
+		productMap := map[string]retailer.Product{
+			"foo": {Manufacturer: "Fender", Model: "Jazzmaster", Price: 1819},
+			"bar": {Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394},
+			"baz": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
+		}
+		store := newProductStore(productMap, nil)
+
+		prds, err := store.FindAll(retailer.Filter{Search: "jazzmaster"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SQ CV 60s Jazzmaster LH LRL OW", "Jazzmaster"}, modelsOf(prds))
+
+		prds, err = store.FindAll(retailer.Filter{Search: "jazzmaster", OrderBy: retailer.OrderByRelevance})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Jazzmaster", "SQ CV 60s Jazzmaster LH LRL OW"}, modelsOf(prds))
+	})
+
+	t.Run("update the index incrementally", func(t *testing.T) {
+		t.Parallel()
+
+		p1 := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Category: "Electric Guitar", Price: 1819}
+		p2 := retailer.Product{Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449}
+		store := NewProductStore()
+		assert.NoError(t, store.Upsert([]retailer.Product{p1, p2}))
+
+		p1.Category = "Offset"
+		assert.NoError(t, store.Upsert([]retailer.Product{p1}))
+		count, err := store.Count(retailer.Filter{Search: "electric"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		count, err = store.Count(retailer.Filter{Search: "offset"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		assert.NoError(t, store.Delete(p2.ID()))
+		count, err = store.Count(retailer.Filter{Search: "epiphone"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		c := store.current()
+		rebuilt := newSearchIndex(c.products)
+		assert.Equal(t, rebuilt.postings, c.index.postings)
+		assert.Equal(t, rebuilt.terms, c.index.terms)
+		assert.Equal(t, rebuilt.totalLength, c.index.totalLength)
+	})
+
+	t.Run("ignore search terms without letters or digits", func(t *testing.T) {
+		t.Parallel()
+
+		productMap := map[string]retailer.Product{
+			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
+			"bar": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
+		}
+		store := newProductStore(productMap, nil)
+
+		count, err := store.Count(retailer.Filter{Search: " - "})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+func modelsOf(prds []retailer.Product) []string {
+	models := make([]string, len(prds))
+	for i, p := range prds {
+		models[i] = p.Model
+	}
+
+	return models
+}
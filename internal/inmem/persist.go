@@ -0,0 +1,272 @@
+package inmem
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	opUpsert string = "upsert"
+	opDelete        = "delete"
+
+	journalFile  = "products.log"
+	snapshotFile = "products.snapshot.json"
+)
+
+type PersistOptions struct {
+	Compress bool
+}
+
+type journal struct {
+	dir     string
+	options PersistOptions
+	file    *os.File
+}
+
+type journalEntry struct {
+	Op       string             `json:"op"`
+	Products []retailer.Product `json:"products,omitempty"`
+	ID       string             `json:"id,omitempty"`
+}
+
+type snapshot struct {
+	Products map[string]retailer.Product      `json:"products"`
+	History  map[string][]retailer.PricePoint `json:"history"`
+}
+
+func OpenProductStore(dir string, options PersistOptions) (*ProductStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create data directory: %w", err)
+	}
+
+	s, err := loadSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, journalFile)
+	if err = s.replay(path); err != nil {
+		return nil, err
+	}
+	p := newProductStore(s.Products, s.History)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open product log: %w", err)
+	}
+	p.journal = &journal{dir: dir, options: options, file: f}
+
+	return p, nil
+}
+
+func (p *ProductStore) Snapshot() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.journal == nil {
+		return errors.New("product store is not persistent")
+	}
+
+	name, other := snapshotFile, snapshotFile+".gz"
+	if p.journal.options.Compress {
+		name, other = other, name
+	}
+	path := filepath.Join(p.journal.dir, name)
+
+	tmp, err := ioutil.TempFile(p.journal.dir, name+".tmp")
+	if err != nil {
+		return fmt.Errorf("could not create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	c := p.current()
+	if err = writeSnapshot(tmp, snapshot{Products: c.products, History: c.history}, p.journal.options.Compress); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write snapshot: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write snapshot: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not write snapshot: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not replace snapshot: %w", err)
+	}
+	_ = os.Remove(filepath.Join(p.journal.dir, other))
+	if err = syncDir(p.journal.dir); err != nil {
+		return fmt.Errorf("could not replace snapshot: %w", err)
+	}
+
+	if err = p.journal.file.Truncate(0); err != nil {
+		return fmt.Errorf("could not truncate product log: %w", err)
+	}
+
+	return nil
+}
+
+// Import snapshots the loaded products right away, as Load does not write them to the log.
+func (p *ProductStore) Import(r io.Reader) error {
+	if p.journal == nil {
+		return errors.New("product store is not persistent")
+	}
+	if err := p.Load(r); err != nil {
+		return err
+	}
+
+	return p.Snapshot()
+}
+
+func (p *ProductStore) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.journal == nil {
+		return nil
+	}
+
+	return p.journal.file.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+func loadSnapshot(dir string) (snapshot, error) {
+	s := snapshot{Products: make(map[string]retailer.Product), History: make(map[string][]retailer.PricePoint)}
+	for _, name := range []string{snapshotFile + ".gz", snapshotFile} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return snapshot{}, fmt.Errorf("could not open snapshot: %w", err)
+		}
+		defer f.Close()
+
+		var r io.Reader = f
+		if filepath.Ext(name) == ".gz" {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return snapshot{}, fmt.Errorf("could not decompress snapshot %s: %w", name, err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+
+		if err = json.NewDecoder(r).Decode(&s); err != nil {
+			return snapshot{}, fmt.Errorf("could not decode snapshot %s: %w", name, err)
+		}
+		if s.Products == nil {
+			s.Products = make(map[string]retailer.Product)
+		}
+		if s.History == nil {
+			s.History = make(map[string][]retailer.PricePoint)
+		}
+
+		return s, nil
+	}
+
+	return s, nil
+}
+
+func (s snapshot) replay(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not open product log: %w", err)
+	}
+	defer f.Close()
+
+	var offset int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not read product log: %w", err)
+		}
+
+		var entry journalEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			break
+		}
+		s.apply(entry)
+		offset += int64(len(line))
+	}
+
+	if err = f.Truncate(offset); err != nil {
+		return fmt.Errorf("could not repair product log: %w", err)
+	}
+
+	return nil
+}
+
+func (s snapshot) apply(entry journalEntry) {
+	switch entry.Op {
+	case opUpsert:
+		for _, product := range entry.Products {
+			s.Products[product.ID()] = product
+			record(s.History, product.ID(), product.PricePoint())
+		}
+	case opDelete:
+		delete(s.Products, entry.ID)
+		delete(s.History, entry.ID)
+	}
+}
+
+func (j *journal) append(entry journalEntry) error {
+	if j == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err = j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("could not append to product log: %w", err)
+	}
+
+	return j.file.Sync()
+}
+
+func writeSnapshot(w io.Writer, s snapshot, compress bool) error {
+	bw := bufio.NewWriter(w)
+	if !compress {
+		if err := json.NewEncoder(bw).Encode(s); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	gz := gzip.NewWriter(bw)
+	if err := json.NewEncoder(gz).Encode(s); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,178 @@
+package inmem
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenProductStore(t *testing.T) {
+	t.Parallel()
+
+	jazzmaster := retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
+	sg := retailer.Product{Retailer: "Musik Produktiv", Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449}
+
+	open := func(t *testing.T, dir string, options PersistOptions) *ProductStore {
+		t.Helper()
+
+		store, err := OpenProductStore(dir, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+
+		return store
+	}
+
+	t.Run("recover upserts and deletes from the log", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster, sg}))
+		assert.NoError(t, store.Delete(sg.ID()))
+		store.Close()
+
+		recovered := open(t, dir, PersistOptions{})
+
+		count, err := recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		p, err := recovered.Get(jazzmaster.ID())
+		assert.NoError(t, err)
+		assert.False(t, p.CreatedAt.IsZero())
+	})
+
+	t.Run("replay the log on top of the latest snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster}))
+		assert.NoError(t, store.Snapshot())
+
+		reduced := jazzmaster
+		reduced.Price = 1699
+		assert.NoError(t, store.Upsert([]retailer.Product{reduced, sg}))
+		store.Close()
+
+		recovered := open(t, dir, PersistOptions{})
+
+		count, err := recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		history, err := recovered.History(jazzmaster.ID())
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, float64(1699), history[1].Price)
+	})
+
+	t.Run("truncate the log after taking a snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster}))
+		assert.NoError(t, store.Snapshot())
+
+		info, err := os.Stat(filepath.Join(dir, journalFile))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), info.Size())
+		assert.FileExists(t, filepath.Join(dir, snapshotFile))
+	})
+
+	t.Run("write compressed snapshots", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster}))
+		assert.NoError(t, store.Snapshot())
+		store.Close()
+
+		store = open(t, dir, PersistOptions{Compress: true})
+		assert.NoError(t, store.Upsert([]retailer.Product{sg}))
+		assert.NoError(t, store.Snapshot())
+		store.Close()
+
+		assert.FileExists(t, filepath.Join(dir, snapshotFile+".gz"))
+		assert.NoFileExists(t, filepath.Join(dir, snapshotFile))
+
+		recovered := open(t, dir, PersistOptions{Compress: true})
+		count, err := recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("discard a partially written log entry", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster}))
+		store.Close()
+
+		f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_WRONLY|os.O_APPEND, 0644)
+		assert.NoError(t, err)
+		_, _ = f.WriteString(`{"op":"upsert","products":[{"retailer":"Musik Produktiv","manu`)
+		f.Close()
+
+		recovered := open(t, dir, PersistOptions{})
+		count, err := recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		assert.NoError(t, recovered.Upsert([]retailer.Product{sg}))
+		recovered.Close()
+
+		recovered = open(t, dir, PersistOptions{})
+		count, err = recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("keep the catalog unchanged if the log cannot be written", func(t *testing.T) {
+		t.Parallel()
+
+		store := open(t, t.TempDir(), PersistOptions{})
+		assert.NoError(t, store.Upsert([]retailer.Product{jazzmaster}))
+		store.journal.file.Close()
+
+		assert.Error(t, store.Upsert([]retailer.Product{sg}))
+		assert.Error(t, store.Delete(jazzmaster.ID()))
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		_, err = store.Get(jazzmaster.ID())
+		assert.NoError(t, err)
+	})
+
+	t.Run("keep imported products after a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		store := open(t, dir, PersistOptions{})
+		dump := `{"Thomann-Fender-AM Pro II Jazzmaster LH MN MYS": {"retailer": "Thomann", "manufacturer": "Fender", "model": "AM Pro II Jazzmaster LH MN MYS"}}`
+		assert.NoError(t, store.Import(strings.NewReader(dump)))
+		store.Close()
+
+		recovered := open(t, dir, PersistOptions{})
+		count, err := recovered.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("return error for a corrupt snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, snapshotFile), []byte(`{"products": {`), 0644))
+
+		_, err := OpenProductStore(dir, PersistOptions{})
+		assert.Error(t, err)
+	})
+}
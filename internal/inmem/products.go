@@ -2,145 +2,143 @@ package inmem
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/chrismeh/lefty/pkg/retailer"
 	"io"
-	"math"
-	"sort"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type ProductStore struct {
-	products map[string]retailer.Product
-	mu       *sync.Mutex
+	catalog atomic.Value
+	mu      *sync.Mutex
+	journal *journal
 }
 
 func NewProductStore() *ProductStore {
-	return &ProductStore{
-		products: make(map[string]retailer.Product),
-		mu:       &sync.Mutex{},
-	}
+	return newProductStore(make(map[string]retailer.Product), make(map[string][]retailer.PricePoint))
 }
 
-func (p *ProductStore) FindAll(f retailer.Filter) ([]retailer.Product, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	prds := make([]retailer.Product, 0, len(p.products))
-	for _, v := range p.products {
-		if productMatchesFilter(v, f) {
-			prds = append(prds, v)
-		}
-	}
-	if len(prds) == 0 {
-		return prds, nil
-	}
+func newProductStore(products map[string]retailer.Product, history map[string][]retailer.PricePoint) *ProductStore {
+	p := &ProductStore{mu: &sync.Mutex{}}
+	p.catalog.Store(newCatalog(products, history))
 
-	sort.Slice(prds, func(i, j int) bool {
-		switch f.OrderBy {
-		case retailer.OrderPriceDesc:
-			return prds[i].Price > prds[j].Price
-		case retailer.OrderByAvailabilityAsc:
-			return prds[i].AvailabilityScore < prds[j].AvailabilityScore
-		case retailer.OrderByAvailabilityDesc:
-			return prds[i].AvailabilityScore > prds[j].AvailabilityScore
-		default:
-			return prds[i].Price < prds[j].Price
-		}
-	})
+	return p
+}
 
-	return paginate(prds, f), nil
+func (p *ProductStore) current() *catalog {
+	return p.catalog.Load().(*catalog)
 }
 
-func (p *ProductStore) Count(f retailer.Filter) int {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func (p *ProductStore) FindAll(f retailer.Filter) ([]retailer.Product, error) {
+	result, err := p.Query(f)
+	return result.Products, err
+}
 
-	var count int
-	for _, v := range p.products {
-		if f.Search == "" || productMatchesFilter(v, f) {
-			count++
-		}
-	}
+func (p *ProductStore) Query(f retailer.Filter) (retailer.QueryResult, error) {
+	return p.current().query(f)
+}
 
-	return count
+func (p *ProductStore) Count(f retailer.Filter) (int, error) {
+	return p.current().count(f), nil
 }
 
 func (p *ProductStore) Upsert(products []retailer.Product) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	prds, history := p.current().clone()
+
 	now := time.Now()
+	saved := make([]retailer.Product, 0, len(products))
+	changed := make([]string, 0, len(products))
 	for _, product := range products {
-		key := buildProductKey(product)
-		if _, exists := p.products[key]; !exists {
+		key := product.ID()
+		if existing, exists := prds[key]; !exists {
 			product.CreatedAt = now
+		} else {
+			product.CreatedAt = existing.CreatedAt
+			product = product.MergeDetails(existing)
 		}
 		product.UpdatedAt = now
-		p.products[key] = product
+		prds[key] = product
+		record(history, key, product.PricePoint())
+		saved = append(saved, product)
+		changed = append(changed, key)
 	}
+	if err := p.journal.append(journalEntry{Op: opUpsert, Products: saved}); err != nil {
+		return err
+	}
+	p.catalog.Store(p.current().next(prds, history, changed))
 
 	return nil
 }
 
-func (p *ProductStore) Dump(w io.Writer) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func (p *ProductStore) Get(id string) (retailer.Product, error) {
+	product, ok := p.current().products[id]
+	if !ok {
+		return retailer.Product{}, retailer.ErrProductNotFound
+	}
 
-	return json.NewEncoder(w).Encode(p.products)
+	return product, nil
 }
 
-func (p *ProductStore) Load(r io.Reader) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func (p *ProductStore) History(id string) ([]retailer.PricePoint, error) {
+	c := p.current()
+	if _, ok := c.products[id]; !ok {
+		return nil, retailer.ErrProductNotFound
+	}
+
+	history := make([]retailer.PricePoint, len(c.history[id]))
+	copy(history, c.history[id])
 
-	return json.NewDecoder(r).Decode(&p.products)
+	return history, nil
 }
 
-func productMatchesFilter(p retailer.Product, f retailer.Filter) bool {
-	if !f.HasFilterCriteria() {
-		return true
+func (p *ProductStore) Delete(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.current().products[id]; !ok {
+		return retailer.ErrProductNotFound
 	}
 
-	if f.Retailer != "" && f.Retailer != p.Retailer {
-		return false
+	if err := p.journal.append(journalEntry{Op: opDelete, ID: id}); err != nil {
+		return err
 	}
 
-	name := strings.ToLower(p.String())
-	search := strings.ToLower(f.Search)
+	prds, history := p.current().clone()
+	delete(prds, id)
+	delete(history, id)
+	p.catalog.Store(p.current().next(prds, history, []string{id}))
 
-	return strings.Contains(name, search)
+	return nil
 }
 
-func paginate(prds []retailer.Product, f retailer.Filter) []retailer.Product {
-	count := uint(len(prds))
-	if f.Page == 0 {
-		f.Page = 1
-	}
-	if f.ProductsPerPage == 0 {
-		f.ProductsPerPage = 50
-	}
-	if f.ProductsPerPage > count {
-		f.ProductsPerPage = count
-	}
-
-	lastPage := math.Ceil(float64(count) / float64(f.ProductsPerPage))
-	if f.Page > uint(lastPage) {
-		f.Page = 1
-	}
+func (p *ProductStore) Dump(w io.Writer) error {
+	c := p.current()
+	return json.NewEncoder(w).Encode(retailer.ProductDump{Products: c.products, History: c.history})
+}
 
-	offset := (f.Page - 1) * f.ProductsPerPage
-	limit := f.ProductsPerPage
+func (p *ProductStore) Load(r io.Reader) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	if offset+limit > count {
-		limit = count - offset
+	loaded, err := retailer.LoadProductDump(r)
+	if err != nil {
+		return err
 	}
 
-	return prds[offset : offset+limit]
-}
+	prds, history := p.current().clone()
+	changed := make([]string, 0, len(loaded.Products))
+	for id, product := range loaded.Products {
+		prds[id] = product
+		if points, ok := loaded.History[id]; ok {
+			history[id] = points
+		}
+		changed = append(changed, id)
+	}
+	p.catalog.Store(p.current().next(prds, history, changed))
 
-func buildProductKey(p retailer.Product) string {
-	return fmt.Sprintf("%s-%s-%s", p.Retailer, p.Manufacturer, p.Model)
+	return nil
 }
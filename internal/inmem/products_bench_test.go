@@ -0,0 +1,95 @@
+package inmem
+
+import (
+	"fmt"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"sync"
+	"testing"
+)
+
+func benchmarkCatalog(n int) []retailer.Product {
+	manufacturers := []string{"Fender", "Gibson", "Epiphone", "Ibanez", "Squier", "Harley Benton"}
+	retailers := []string{"Thomann", "Musik Produktiv", "Music Store", "Gear4music"}
+
+	prds := make([]retailer.Product, n)
+	for i := range prds {
+		prds[i] = retailer.Product{
+			Retailer:          retailers[i%len(retailers)],
+			Manufacturer:      manufacturers[i%len(manufacturers)],
+			Model:             fmt.Sprintf("Model %d LH", i),
+			Price:             float64(199 + (i*37)%2800),
+			AvailabilityScore: 1 + i%4,
+		}
+	}
+
+	return prds
+}
+
+func BenchmarkProductStore_FindAll(b *testing.B) {
+	filters := map[string]retailer.Filter{
+		"first page":        {},
+		"search":            {Search: "gibson"},
+		"relevance":         {Search: "gibson lh", OrderBy: retailer.OrderByRelevance},
+		"retailer and page": {Retailer: "Thomann", Page: 3, OrderBy: retailer.OrderPriceDesc},
+	}
+
+	store := NewProductStore()
+	_ = store.Upsert(benchmarkCatalog(10000))
+
+	for name, f := range filters {
+		f := f
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, _ = store.FindAll(f)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkProductStore_FindAllWhileUpserting(b *testing.B) {
+	prds := benchmarkCatalog(10000)
+	store := NewProductStore()
+	_ = store.Upsert(prds)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				prds[i%len(prds)].Price++
+				_ = store.Upsert(prds)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = store.FindAll(retailer.Filter{})
+			_, _ = store.Count(retailer.Filter{})
+		}
+	})
+	b.StopTimer()
+
+	close(done)
+	wg.Wait()
+}
+
+func BenchmarkProductStore_Upsert(b *testing.B) {
+	prds := benchmarkCatalog(10000)
+	store := NewProductStore()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = store.Upsert(prds)
+	}
+}
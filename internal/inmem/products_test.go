@@ -1,8 +1,10 @@
 package inmem
 
 import (
+	"bytes"
 	"github.com/chrismeh/lefty/pkg/retailer"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -18,9 +20,10 @@ func TestProductStore_Count(t *testing.T) {
 			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
 			"bar": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
-		count := store.Count(retailer.Filter{})
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
 		assert.Equal(t, 2, count)
 	})
 
@@ -31,9 +34,24 @@ func TestProductStore_Count(t *testing.T) {
 			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
 			"bar": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
+
+		count, err := store.Count(retailer.Filter{Search: "Fender"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("return number of products of the selected retailer", func(t *testing.T) {
+		t.Parallel()
+
+		productMap := map[string]retailer.Product{
+			"foo": {Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
+			"bar": {Retailer: "Musik Produktiv", Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
+		}
+		store := newProductStore(productMap, nil)
 
-		count := store.Count(retailer.Filter{Search: "Fender"})
+		count, err := store.Count(retailer.Filter{Retailer: "Thomann"})
+		assert.NoError(t, err)
 		assert.Equal(t, 1, count)
 	})
 }
@@ -45,7 +63,7 @@ func TestProductStore_FindAll(t *testing.T) {
 		t.Parallel()
 
 		p := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS"}
-		store := ProductStore{products: map[string]retailer.Product{"foo": p}, mu: &sync.Mutex{}}
+		store := newProductStore(map[string]retailer.Product{"foo": p}, nil)
 
 		prds, err := store.FindAll(retailer.Filter{})
 		assert.NoError(t, err)
@@ -61,7 +79,7 @@ func TestProductStore_FindAll(t *testing.T) {
 		p1 := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
 		p2 := retailer.Product{Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394}
 		productMap := map[string]retailer.Product{"foo": p1, "bar": p2}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		filter := retailer.Filter{Page: 2, ProductsPerPage: 1}
 		prds, err := store.FindAll(filter)
@@ -78,7 +96,7 @@ func TestProductStore_FindAll(t *testing.T) {
 			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
 			"bar": {Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		tests := []struct {
 			Name            string
@@ -87,7 +105,6 @@ func TestProductStore_FindAll(t *testing.T) {
 		}{
 			{Name: "products per page is larger than product count", Page: 1, ProductsPerPage: 50},
 			{Name: "page is zero", Page: 0, ProductsPerPage: 50},
-			{Name: "page does not exist", Page: 100, ProductsPerPage: 50},
 		}
 
 		for _, tt := range tests {
@@ -103,6 +120,19 @@ func TestProductStore_FindAll(t *testing.T) {
 		}
 	})
 
+	t.Run("return error if page does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		productMap := map[string]retailer.Product{
+			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
+			"bar": {Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394},
+		}
+		store := newProductStore(productMap, nil)
+
+		_, err := store.FindAll(retailer.Filter{Page: 100, ProductsPerPage: 50})
+		assert.ErrorIs(t, err, retailer.ErrPageOutOfRange)
+	})
+
 	t.Run("return slice of remaining products on the last page", func(t *testing.T) {
 		t.Parallel()
 
@@ -111,7 +141,7 @@ func TestProductStore_FindAll(t *testing.T) {
 			"bar": {Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394},
 			"baz": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH 3TSB", Price: 1799},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		prds, err := store.FindAll(retailer.Filter{Page: 2, ProductsPerPage: 2})
 		assert.NoError(t, err)
@@ -127,7 +157,7 @@ func TestProductStore_FindAll(t *testing.T) {
 			"foo": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
 			"bar": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		tests := []struct {
 			Name          string
@@ -169,7 +199,7 @@ func TestProductStore_FindAll(t *testing.T) {
 			"foo": {Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
 			"bar": {Retailer: "Musik Produktiv", Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		prds, err := store.FindAll(retailer.Filter{Retailer: "Thomann"})
 		assert.NoError(t, err)
@@ -184,7 +214,7 @@ func TestProductStore_FindAll(t *testing.T) {
 		p1 := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
 		p2 := retailer.Product{Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394}
 		productMap := map[string]retailer.Product{"foo": p1, "bar": p2}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		prds, err := store.FindAll(retailer.Filter{})
 		assert.NoError(t, err)
@@ -199,7 +229,7 @@ func TestProductStore_FindAll(t *testing.T) {
 		p1 := retailer.Product{Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394}
 		p2 := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
 		productMap := map[string]retailer.Product{"foo": p1, "bar": p2}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		prds, err := store.FindAll(retailer.Filter{OrderBy: retailer.OrderPriceDesc})
 		assert.NoError(t, err)
@@ -214,7 +244,7 @@ func TestProductStore_FindAll(t *testing.T) {
 			"bar": {Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819, AvailabilityScore: 1},
 			"baz": {Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449, AvailabilityScore: 3},
 		}
-		store := ProductStore{products: productMap, mu: &sync.Mutex{}}
+		store := newProductStore(productMap, nil)
 
 		tests := []struct {
 			Name          string
@@ -248,11 +278,11 @@ func TestProductStore_Upsert(t *testing.T) {
 		err := store.Upsert([]retailer.Product{p})
 
 		assert.NoError(t, err)
-		assert.Len(t, store.products, 1)
+		assert.Len(t, store.current().products, 1)
 
-		pk := buildProductKey(p)
-		assert.Equal(t, "Fender", store.products[pk].Manufacturer)
-		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", store.products[pk].Model)
+		pk := p.ID()
+		assert.Equal(t, "Fender", store.current().products[pk].Manufacturer)
+		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", store.current().products[pk].Model)
 	})
 
 	t.Run("set timestamps when saving a new product", func(t *testing.T) {
@@ -263,10 +293,10 @@ func TestProductStore_Upsert(t *testing.T) {
 
 		_ = store.Upsert([]retailer.Product{p})
 
-		pk := buildProductKey(p)
-		assert.Equal(t, store.products[pk].CreatedAt, store.products[pk].UpdatedAt)
-		assert.False(t, store.products[pk].CreatedAt.IsZero())
-		assert.False(t, store.products[pk].UpdatedAt.IsZero())
+		pk := p.ID()
+		assert.Equal(t, store.current().products[pk].CreatedAt, store.current().products[pk].UpdatedAt)
+		assert.False(t, store.current().products[pk].CreatedAt.IsZero())
+		assert.False(t, store.current().products[pk].UpdatedAt.IsZero())
 	})
 
 	t.Run("setUpdatedAt timestamp when saving an existing product", func(t *testing.T) {
@@ -278,12 +308,102 @@ func TestProductStore_Upsert(t *testing.T) {
 			CreatedAt:    time.Date(2014, 8, 6, 23, 0, 0, 0, time.UTC),
 			UpdatedAt:    time.Date(2014, 8, 6, 23, 0, 0, 0, time.UTC),
 		}
-		pk := buildProductKey(p)
-		store := ProductStore{products: map[string]retailer.Product{pk: p}, mu: &sync.Mutex{}}
+		pk := p.ID()
+		store := newProductStore(map[string]retailer.Product{pk: p}, nil)
 
 		_ = store.Upsert([]retailer.Product{p})
 
-		assert.NotEqual(t, store.products[pk].CreatedAt, store.products[pk].UpdatedAt)
+		assert.NotEqual(t, store.current().products[pk].CreatedAt, store.current().products[pk].UpdatedAt)
+
+	})
+
+	t.Run("keep product details when saving an existing product without details", func(t *testing.T) {
+		t.Parallel()
+
+		p := retailer.Product{
+			Manufacturer: "Fender",
+			Model:        "AM Pro II Jazzmaster LH MN MYS",
+			Specs:        map[string]string{retailer.SpecScaleLength: "648 mm"},
+		}
+		pk := p.ID()
+		store := newProductStore(map[string]retailer.Product{pk: p}, nil)
+
+		_ = store.Upsert([]retailer.Product{{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1799}})
 
+		assert.Equal(t, float64(1799), store.current().products[pk].Price)
+		assert.Equal(t, "648 mm", store.current().products[pk].Specs[retailer.SpecScaleLength])
+	})
+}
+
+func TestProductStore_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restore price history of a dump", func(t *testing.T) {
+		t.Parallel()
+
+		p := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
+		store := NewProductStore()
+		_ = store.Upsert([]retailer.Product{p})
+		p.Price = 1799
+		_ = store.Upsert([]retailer.Product{p})
+
+		var buf bytes.Buffer
+		assert.NoError(t, store.Dump(&buf))
+
+		restored := NewProductStore()
+		assert.NoError(t, restored.Load(&buf))
+
+		history, err := restored.History(p.ID())
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+	})
+
+	t.Run("load a dump without price history", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewProductStore()
+		err := store.Load(strings.NewReader(`{"Thomann-Fender-AM Pro II Jazzmaster LH MN MYS": {"retailer": "Thomann", "manufacturer": "Fender", "model": "AM Pro II Jazzmaster LH MN MYS"}}`))
+
+		assert.NoError(t, err)
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestProductStore_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serve consistent results to readers while upserting", func(t *testing.T) {
+		t.Parallel()
+
+		p1 := retailer.Product{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
+		p2 := retailer.Product{Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394}
+		store := NewProductStore()
+		_ = store.Upsert([]retailer.Product{p1, p2})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					prds, err := store.FindAll(retailer.Filter{})
+					assert.NoError(t, err)
+					assert.Len(t, prds, 2)
+					assert.LessOrEqual(t, prds[0].Price, prds[1].Price)
+				}
+			}()
+		}
+
+		for i := 0; i < 200; i++ {
+			p2.Price = float64(394 + i)
+			assert.NoError(t, store.Upsert([]retailer.Product{p2}))
+		}
+		wg.Wait()
+
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
 	})
 }
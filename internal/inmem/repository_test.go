@@ -0,0 +1,25 @@
+package inmem
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/chrismeh/lefty/pkg/retailer/retailertest"
+	"testing"
+)
+
+func TestProductStore_ProductRepository(t *testing.T) {
+	retailertest.RunProductRepositoryTests(t, func(t *testing.T) retailer.ProductRepository {
+		return NewProductStore()
+	})
+}
+
+func TestProductStore_PersistentProductRepository(t *testing.T) {
+	retailertest.RunProductRepositoryTests(t, func(t *testing.T) retailer.ProductRepository {
+		store, err := OpenProductStore(t.TempDir(), PersistOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+
+		return store
+	})
+}
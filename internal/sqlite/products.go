@@ -0,0 +1,458 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"io"
+	_ "modernc.org/sqlite"
+	"strings"
+	"time"
+)
+
+var migrations = []string{
+	`CREATE TABLE products (
+		id                 TEXT PRIMARY KEY,
+		retailer           TEXT NOT NULL,
+		manufacturer       TEXT NOT NULL,
+		model              TEXT NOT NULL,
+		search_name        TEXT NOT NULL,
+		category           TEXT NOT NULL,
+		condition          TEXT NOT NULL,
+		is_available       INTEGER NOT NULL,
+		availability_info  TEXT NOT NULL,
+		availability_score INTEGER NOT NULL,
+		price              REAL NOT NULL,
+		currency           TEXT NOT NULL,
+		gtin               TEXT NOT NULL,
+		product_url        TEXT NOT NULL,
+		thumbnail_url      TEXT NOT NULL,
+		specs              TEXT,
+		details_updated_at INTEGER,
+		created_at         INTEGER,
+		updated_at         INTEGER
+	);
+	CREATE INDEX products_retailer ON products (retailer);
+	CREATE INDEX products_price ON products (price, id);
+	CREATE INDEX products_availability ON products (availability_score, id);
+	CREATE TABLE price_history (
+		id           INTEGER PRIMARY KEY,
+		product_id   TEXT NOT NULL,
+		price        REAL NOT NULL,
+		currency     TEXT NOT NULL,
+		is_available INTEGER NOT NULL,
+		recorded_at  INTEGER
+	);
+	CREATE INDEX price_history_product ON price_history (product_id, id);`,
+	`ALTER TABLE products ADD COLUMN storefront TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE products ADD COLUMN listing_id TEXT NOT NULL DEFAULT '';`,
+	`CREATE VIRTUAL TABLE product_search USING fts5(name, other, tokenize = 'unicode61 remove_diacritics 0');
+	INSERT INTO product_search (rowid, name, other)
+		SELECT rowid, manufacturer || ' ' || model,
+			category || ' ' || condition || ' ' || coalesce((SELECT group_concat(value, ' ') FROM json_each(products.specs)), '')
+		FROM products;
+	ALTER TABLE products DROP COLUMN search_name;`,
+}
+
+const productColumns = `retailer, storefront, listing_id, manufacturer, model, category, condition, is_available, availability_info,
+	availability_score, price, currency, gtin, product_url, thumbnail_url, specs, details_updated_at, created_at, updated_at`
+
+var orderClauses = map[string]string{
+	retailer.OrderPriceDesc:          "price DESC, id DESC",
+	retailer.OrderByAvailabilityAsc:  "availability_score ASC, id",
+	retailer.OrderByAvailabilityDesc: "availability_score DESC, id DESC",
+	retailer.OrderByRelevance:        "bm25(product_search, 2.0, 1.0), price ASC, id",
+}
+
+type ProductStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+func Open(path string) (*ProductStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err = migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &ProductStore{db: db, now: time.Now}, nil
+}
+
+func (p *ProductStore) Close() error {
+	return p.db.Close()
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("could not read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("could not migrate schema to version %d: %w", i+1, err)
+		}
+		if _, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("could not migrate schema to version %d: %w", i+1, err)
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *ProductStore) FindAll(f retailer.Filter) ([]retailer.Product, error) {
+	result, err := p.Query(f)
+	return result.Products, err
+}
+
+func (p *ProductStore) Query(f retailer.Filter) (retailer.QueryResult, error) {
+	var result retailer.QueryResult
+	err := p.transaction(func(tx *sql.Tx) error {
+		from, args := fromClause(f)
+
+		var total uint
+		if err := tx.QueryRow("SELECT COUNT(*)"+from, args...).Scan(&total); err != nil {
+			return fmt.Errorf("could not count products: %w", err)
+		}
+
+		w, err := f.Window(total)
+		if err != nil {
+			return err
+		}
+
+		order, ok := orderClauses[f.OrderBy]
+		if !ok || (f.OrderBy == retailer.OrderByRelevance && matchExpression(f.Search) == "") {
+			order = "price ASC, id"
+		}
+
+		query := fmt.Sprintf("SELECT %s%s ORDER BY %s LIMIT ? OFFSET ?", productColumns, from, order)
+		rows, err := tx.Query(query, append(args, w.Limit, w.Offset)...)
+		if err != nil {
+			return fmt.Errorf("could not query products: %w", err)
+		}
+		defer rows.Close()
+
+		prds := make([]retailer.Product, 0, w.Limit)
+		for rows.Next() {
+			product, err := scanProduct(rows)
+			if err != nil {
+				return err
+			}
+			prds = append(prds, product)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		result = retailer.NewQueryResult(prds, total, w)
+		return nil
+	})
+
+	return result, err
+}
+
+func (p *ProductStore) Count(f retailer.Filter) (int, error) {
+	from, args := fromClause(f)
+
+	var count int
+	if err := p.db.QueryRow("SELECT COUNT(*)"+from, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("could not count products: %w", err)
+	}
+
+	return count, nil
+}
+
+func (p *ProductStore) Get(id string) (retailer.Product, error) {
+	return getProduct(p.db, id)
+}
+
+func (p *ProductStore) Upsert(products []retailer.Product) error {
+	return p.transaction(func(tx *sql.Tx) error {
+		now := p.now()
+		for _, product := range products {
+			existing, err := getProduct(tx, product.ID())
+			switch {
+			case errors.Is(err, retailer.ErrProductNotFound):
+				product.CreatedAt = now
+			case err != nil:
+				return err
+			default:
+				product.CreatedAt = existing.CreatedAt
+				product = product.MergeDetails(existing)
+			}
+			product.UpdatedAt = now
+
+			if err = saveProduct(tx, product); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *ProductStore) History(id string) ([]retailer.PricePoint, error) {
+	if _, err := p.Get(id); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.Query("SELECT price, currency, is_available, recorded_at FROM price_history WHERE product_id = ? ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("could not query price history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]retailer.PricePoint, 0)
+	for rows.Next() {
+		var pp retailer.PricePoint
+		var recordedAt sql.NullInt64
+		if err = rows.Scan(&pp.Price, &pp.Currency, &pp.IsAvailable, &recordedAt); err != nil {
+			return nil, err
+		}
+		pp.RecordedAt = fromUnixNano(recordedAt)
+		history = append(history, pp)
+	}
+
+	return history, rows.Err()
+}
+
+func (p *ProductStore) Delete(id string) error {
+	return p.transaction(func(tx *sql.Tx) error {
+		if err := unindexProduct(tx, id); err != nil {
+			return err
+		}
+
+		res, err := tx.Exec("DELETE FROM products WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("could not delete product: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return retailer.ErrProductNotFound
+		}
+
+		_, err = tx.Exec("DELETE FROM price_history WHERE product_id = ?", id)
+		return err
+	})
+}
+
+func (p *ProductStore) Import(r io.Reader) error {
+	dump, err := retailer.LoadProductDump(r)
+	if err != nil {
+		return err
+	}
+
+	return p.transaction(func(tx *sql.Tx) error {
+		for id, product := range dump.Products {
+			for _, point := range dump.History[id] {
+				if err := recordPricePoint(tx, product.ID(), point); err != nil {
+					return err
+				}
+			}
+			if err := saveProduct(tx, product); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *ProductStore) transaction(fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getProduct(q queryer, id string) (retailer.Product, error) {
+	row := q.QueryRow(fmt.Sprintf("SELECT %s FROM products WHERE id = ?", productColumns), id)
+
+	product, err := scanProduct(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return retailer.Product{}, retailer.ErrProductNotFound
+	}
+
+	return product, err
+}
+
+func saveProduct(tx *sql.Tx, product retailer.Product) error {
+	var specs sql.NullString
+	if product.Specs != nil {
+		data, err := json.Marshal(product.Specs)
+		if err != nil {
+			return err
+		}
+		specs = sql.NullString{String: string(data), Valid: true}
+	}
+
+	id := product.ID()
+	if err := unindexProduct(tx, id); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`INSERT OR REPLACE INTO products (id, `+productColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, product.Retailer, product.Storefront, product.ListingID, product.Manufacturer, product.Model, product.Category,
+		product.Condition, product.IsAvailable, product.AvailabilityInfo, product.AvailabilityScore, product.Price,
+		product.Currency, product.GTIN, product.ProductURL, product.ThumbnailURL, specs,
+		toUnixNano(product.DetailsUpdatedAt), toUnixNano(product.CreatedAt), toUnixNano(product.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("could not save product %s: %w", id, err)
+	}
+	if err = indexProduct(tx, res, product); err != nil {
+		return err
+	}
+
+	return recordPricePoint(tx, id, product.PricePoint())
+}
+
+func indexProduct(tx *sql.Tx, res sql.Result, product retailer.Product) error {
+	rowID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	other := []string{product.Category, product.Condition}
+	for _, value := range product.Specs {
+		other = append(other, value)
+	}
+
+	_, err = tx.Exec("INSERT INTO product_search (rowid, name, other) VALUES (?, ?, ?)", rowID, product.String(), strings.Join(other, " "))
+	if err != nil {
+		return fmt.Errorf("could not index product %s: %w", product.ID(), err)
+	}
+
+	return nil
+}
+
+func unindexProduct(tx *sql.Tx, id string) error {
+	_, err := tx.Exec("DELETE FROM product_search WHERE rowid IN (SELECT rowid FROM products WHERE id = ?)", id)
+	if err != nil {
+		return fmt.Errorf("could not remove product %s from search: %w", id, err)
+	}
+
+	return nil
+}
+
+func recordPricePoint(tx *sql.Tx, id string, point retailer.PricePoint) error {
+	var last retailer.PricePoint
+	err := tx.QueryRow("SELECT price, currency, is_available FROM price_history WHERE product_id = ? ORDER BY id DESC LIMIT 1", id).
+		Scan(&last.Price, &last.Currency, &last.IsAvailable)
+	if err == nil && !last.Differs(point) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("could not read price history of %s: %w", id, err)
+	}
+
+	_, err = tx.Exec("INSERT INTO price_history (product_id, price, currency, is_available, recorded_at) VALUES (?, ?, ?, ?, ?)",
+		id, point.Price, point.Currency, point.IsAvailable, toUnixNano(point.RecordedAt))
+	if err != nil {
+		return fmt.Errorf("could not record price of %s: %w", id, err)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(s scanner) (retailer.Product, error) {
+	var p retailer.Product
+	var specs sql.NullString
+	var detailsUpdatedAt, createdAt, updatedAt sql.NullInt64
+
+	err := s.Scan(&p.Retailer, &p.Storefront, &p.ListingID, &p.Manufacturer, &p.Model, &p.Category, &p.Condition, &p.IsAvailable, &p.AvailabilityInfo,
+		&p.AvailabilityScore, &p.Price, &p.Currency, &p.GTIN, &p.ProductURL, &p.ThumbnailURL, &specs,
+		&detailsUpdatedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return retailer.Product{}, err
+	}
+
+	if specs.Valid {
+		if err = json.Unmarshal([]byte(specs.String), &p.Specs); err != nil {
+			return retailer.Product{}, fmt.Errorf("could not decode specs of %s: %w", p.ID(), err)
+		}
+	}
+	p.DetailsUpdatedAt = fromUnixNano(detailsUpdatedAt)
+	p.CreatedAt = fromUnixNano(createdAt)
+	p.UpdatedAt = fromUnixNano(updatedAt)
+
+	return p, nil
+}
+
+func fromClause(f retailer.Filter) (string, []interface{}) {
+	from := " FROM products"
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 2)
+
+	if match := matchExpression(f.Search); match != "" {
+		from += " JOIN product_search ON product_search.rowid = products.rowid"
+		conditions = append(conditions, "product_search MATCH ?")
+		args = append(args, match)
+	}
+	if f.Retailer != "" {
+		conditions = append(conditions, "retailer = ?")
+		args = append(args, f.Retailer)
+	}
+	if len(conditions) == 0 {
+		return from, args
+	}
+
+	return from + " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func matchExpression(search string) string {
+	terms := retailer.Tokenize(search)
+	for i, term := range terms {
+		terms[i] = `"` + term + `"*`
+	}
+
+	return strings.Join(terms, " AND ")
+}
+
+func toUnixNano(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+func fromUnixNano(n sql.NullInt64) time.Time {
+	if !n.Valid {
+		return time.Time{}
+	}
+
+	return time.Unix(0, n.Int64).UTC()
+}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("migrate schema to the latest version", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+
+		var version int
+		assert.NoError(t, store.db.QueryRow("PRAGMA user_version").Scan(&version))
+		assert.Equal(t, len(migrations), version)
+	})
+
+	t.Run("keep products when reopening the database", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "lefty.db")
+		store, err := Open(path)
+		assert.NoError(t, err)
+
+		p := retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819}
+		assert.NoError(t, store.Upsert([]retailer.Product{p}))
+		assert.NoError(t, store.Close())
+
+		store, err = Open(path)
+		assert.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("index existing products for search", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "lefty.db")
+		db, err := sql.Open("sqlite", path)
+		assert.NoError(t, err)
+		for i, m := range migrations[:3] {
+			_, err = db.Exec(m)
+			assert.NoError(t, err)
+			_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			assert.NoError(t, err)
+		}
+		_, err = db.Exec(`INSERT INTO products (id, search_name, ` + productColumns + `)
+			VALUES ('Thomann-Epiphone-ES-335 LH', 'epiphone es-335 lh', 'Thomann', '', '', 'Epiphone', 'ES-335 LH', 'Semi-Hollow', 'new',
+				1, '', 1, 549, 'EUR', '', '', '', '{"scale_length": "628 mm"}', NULL, NULL, NULL)`)
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		store, err := Open(path)
+		assert.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+
+		count, err := store.Count(retailer.Filter{Search: "epiphone"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		count, err = store.Count(retailer.Filter{Search: "hollow 628"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		_, err = store.db.Exec("SELECT search_name FROM products")
+		assert.Error(t, err)
+	})
+}
+
+func TestProductStore_Count(t *testing.T) {
+	t.Parallel()
+
+	t.Run("return error if products cannot be counted", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+		assert.NoError(t, store.Close())
+
+		_, err := store.Count(retailer.Filter{})
+		assert.Error(t, err)
+	})
+}
+
+func TestProductStore_Upsert(t *testing.T) {
+	t.Parallel()
+
+	t.Run("roll back all products of a crawl if one fails", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+		_, err := store.db.Exec("CREATE TRIGGER reject_used BEFORE INSERT ON products WHEN NEW.condition = 'used' BEGIN SELECT RAISE(ABORT, 'rejected'); END")
+		assert.NoError(t, err)
+
+		err = store.Upsert([]retailer.Product{
+			{Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819},
+			{Retailer: "Reverb", Manufacturer: "Gibson", Model: "Les Paul Standard LH", Price: 1999, Condition: retailer.ConditionUsed},
+		})
+		assert.Error(t, err)
+
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestProductStore_Import(t *testing.T) {
+	t.Parallel()
+
+	t.Run("import products of a products.json dump", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+		dump := `{
+			"Thomann-Fender-AM Pro II Jazzmaster LH MN MYS": {
+				"retailer": "Thomann", "manufacturer": "Fender", "model": "AM Pro II Jazzmaster LH MN MYS",
+				"price": 1819, "currency": "EUR", "specs": {"scale_length": "648 mm"},
+				"created_at": "2022-01-10T08:00:00Z", "updated_at": "2022-03-14T08:00:00Z"
+			},
+			"Musik Produktiv-Epiphone-SG Standard Alpine White LH": {
+				"retailer": "Musik Produktiv", "manufacturer": "Epiphone", "model": "SG Standard Alpine White LH", "price": 449
+			}
+		}`
+
+		assert.NoError(t, store.Import(strings.NewReader(dump)))
+		count, err := store.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		p, err := store.Get("Thomann-Fender-AM Pro II Jazzmaster LH MN MYS")
+		assert.NoError(t, err)
+		assert.Equal(t, "648 mm", p.Specs[retailer.SpecScaleLength])
+		assert.Equal(t, time.Date(2022, 1, 10, 8, 0, 0, 0, time.UTC), p.CreatedAt)
+		assert.Equal(t, time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC), p.UpdatedAt)
+
+		history, err := store.History(p.ID())
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("import price history of a dump", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+		dump := `{
+			"products": {
+				"Thomann-Fender-AM Pro II Jazzmaster LH MN MYS": {
+					"retailer": "Thomann", "manufacturer": "Fender", "model": "AM Pro II Jazzmaster LH MN MYS",
+					"price": 1799, "currency": "EUR", "updated_at": "2022-03-14T08:00:00Z"
+				}
+			},
+			"history": {
+				"Thomann-Fender-AM Pro II Jazzmaster LH MN MYS": [
+					{"price": 1819, "currency": "EUR", "recorded_at": "2022-01-10T08:00:00Z"},
+					{"price": 1799, "currency": "EUR", "recorded_at": "2022-03-14T08:00:00Z"}
+				]
+			}
+		}`
+
+		assert.NoError(t, store.Import(strings.NewReader(dump)))
+
+		history, err := store.History("Thomann-Fender-AM Pro II Jazzmaster LH MN MYS")
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, float64(1819), history[0].Price)
+	})
+
+	t.Run("return error for malformed dump", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestProductStore(t)
+
+		assert.Error(t, store.Import(strings.NewReader(`{"foo": [`)))
+	})
+}
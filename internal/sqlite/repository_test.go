@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/chrismeh/lefty/pkg/retailer/retailertest"
+	"path/filepath"
+	"testing"
+)
+
+func TestProductStore_ProductRepository(t *testing.T) {
+	retailertest.RunProductRepositoryTests(t, func(t *testing.T) retailer.ProductRepository {
+		return newTestProductStore(t)
+	})
+}
+
+func newTestProductStore(t *testing.T) *ProductStore {
+	t.Helper()
+
+	store, err := Open(filepath.Join(t.TempDir(), "lefty.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
@@ -0,0 +1,234 @@
+package retailer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type ArchiveRecord struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Status    int       `json:"status"`
+	Body      []byte    `json:"body,omitempty"`
+}
+
+type ArchivingClient struct {
+	http httpGetter
+	dir  string
+	now  func() time.Time
+}
+
+func NewArchivingClient(http httpGetter, dir string) (*ArchivingClient, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create archive directory: %w", err)
+	}
+
+	return &ArchivingClient{http: http, dir: dir, now: time.Now}, nil
+}
+
+func (a *ArchivingClient) Get(url string) (*http.Response, error) {
+	resp, err := a.http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := newArchiveWriter(a.dir, ArchiveRecord{URL: url, FetchedAt: a.now().UTC(), Status: resp.StatusCode})
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	resp.Body = &archivedBody{body: resp.Body, r: io.TeeReader(resp.Body, w), w: w}
+	return resp, nil
+}
+
+// archivedBody writes the response body to the archive while it is read. Whatever the caller
+// leaves unread is archived on Close.
+type archivedBody struct {
+	body io.ReadCloser
+	r    io.Reader
+	w    *archiveWriter
+}
+
+func (b *archivedBody) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+func (b *archivedBody) Close() error {
+	_, err := io.Copy(ioutil.Discard, b.r)
+	if cerr := b.body.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		b.w.abort()
+		return fmt.Errorf("could not archive %s: %w", b.w.url, err)
+	}
+
+	return b.w.Close()
+}
+
+type archiveWriter struct {
+	url  string
+	path string
+	tmp  *os.File
+	zw   *gzip.Writer
+}
+
+func newArchiveWriter(dir string, record ArchiveRecord) (*archiveWriter, error) {
+	hash := sha1.Sum([]byte(record.URL))
+	name := fmt.Sprintf("%s-%s.json.gz", record.FetchedAt.Format("20060102T150405.000000000"), hex.EncodeToString(hash[:8]))
+
+	tmp, err := ioutil.TempFile(dir, name+".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("could not create archive record: %w", err)
+	}
+
+	w := &archiveWriter{url: record.URL, path: filepath.Join(dir, name), tmp: tmp, zw: gzip.NewWriter(tmp)}
+	if err = json.NewEncoder(w.zw).Encode(record); err != nil {
+		w.abort()
+		return nil, fmt.Errorf("could not write archive record: %w", err)
+	}
+
+	return w, nil
+}
+
+func (w *archiveWriter) Write(p []byte) (int, error) {
+	return w.zw.Write(p)
+}
+
+func (w *archiveWriter) Close() error {
+	err := w.zw.Close()
+	if cerr := w.tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(w.tmp.Name(), w.path)
+	}
+	if err != nil {
+		os.Remove(w.tmp.Name())
+		return fmt.Errorf("could not write archive record: %w", err)
+	}
+
+	return nil
+}
+
+func (w *archiveWriter) abort() {
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+}
+
+type Archive struct {
+	files map[string]string
+}
+
+func OpenArchive(dir string) (*Archive, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json.gz"))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{files: make(map[string]string)}
+	fetchedAt := make(map[string]time.Time)
+	for _, file := range files {
+		record, body, err := openArchiveRecord(file)
+		if err != nil {
+			return nil, err
+		}
+		body.Close()
+
+		if latest, ok := fetchedAt[record.URL]; ok && latest.After(record.FetchedAt) {
+			continue
+		}
+		fetchedAt[record.URL] = record.FetchedAt
+		a.files[record.URL] = file
+	}
+
+	return a, nil
+}
+
+func (a *Archive) Len() int {
+	return len(a.files)
+}
+
+func (a *Archive) Get(url string) (*http.Response, error) {
+	file, ok := a.files[url]
+	if !ok {
+		return nil, fmt.Errorf("%s is not archived", url)
+	}
+
+	record, body, err := openArchiveRecord(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", record.Status, http.StatusText(record.Status)),
+		StatusCode: record.Status,
+		Body:       body,
+	}, nil
+}
+
+func writeArchiveRecord(dir string, record ArchiveRecord) error {
+	body := record.Body
+	record.Body = nil
+
+	w, err := newArchiveWriter(dir, record)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(body); err != nil {
+		w.abort()
+		return fmt.Errorf("could not write archive record: %w", err)
+	}
+
+	return w.Close()
+}
+
+// openArchiveRecord returns the header of a record and a reader for its body. Records written before
+// bodies were streamed keep the body in the header.
+func openArchiveRecord(file string) (ArchiveRecord, io.ReadCloser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return ArchiveRecord{}, nil, fmt.Errorf("could not open archive record: %w", err)
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return ArchiveRecord{}, nil, fmt.Errorf("could not read archive record %s: %w", filepath.Base(file), err)
+	}
+
+	br := bufio.NewReader(zr)
+	header, err := br.ReadBytes('\n')
+	var record ArchiveRecord
+	if err == nil {
+		err = json.Unmarshal(header, &record)
+	}
+	if err != nil {
+		f.Close()
+		return ArchiveRecord{}, nil, fmt.Errorf("could not decode archive record %s: %w", filepath.Base(file), err)
+	}
+
+	if record.Body != nil {
+		f.Close()
+		return record, ioutil.NopCloser(bytes.NewReader(record.Body)), nil
+	}
+
+	return record, readCloser{Reader: br, Closer: f}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
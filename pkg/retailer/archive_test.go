@@ -0,0 +1,191 @@
+package retailer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchivingClient_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("archive compressed responses with url, timestamp and status", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ac, err := NewArchivingClient(newTestHTTPClientForFixture("thomann_basses_six_strings.html"), dir)
+		assert.NoError(t, err)
+		ac.now = func() time.Time { return time.Date(2022, 3, 14, 8, 30, 0, 0, time.UTC) }
+
+		resp, err := ac.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		fixture, err := ioutil.ReadFile(filepath.Join("testdata", "thomann_basses_six_strings.html"))
+		assert.NoError(t, err)
+		assert.Equal(t, fixture, body)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json.gz"))
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		record, err := readArchiveRecord(files[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1", record.URL)
+		assert.Equal(t, time.Date(2022, 3, 14, 8, 30, 0, 0, time.UTC), record.FetchedAt)
+		assert.Equal(t, fixture, record.Body)
+	})
+
+	t.Run("archive the unread rest of a body when it is closed", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		httpClient := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("<rss><item>first</item><item>second</item></rss>"))}, nil
+		}}
+		ac, err := NewArchivingClient(httpClient, dir)
+		assert.NoError(t, err)
+
+		resp, err := ac.Get("https://www.saitenwerk.example/feeds/google.xml")
+		assert.NoError(t, err)
+		_, err = resp.Body.Read(make([]byte, 5))
+		assert.NoError(t, err)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json.gz"))
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+
+		assert.NoError(t, resp.Body.Close())
+		files, err = filepath.Glob(filepath.Join(dir, "*.json.gz"))
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		record, err := readArchiveRecord(files[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "<rss><item>first</item><item>second</item></rss>", string(record.Body))
+	})
+
+	t.Run("archive error responses", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		httpClient := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(bytes.NewBufferString("maintenance"))}, nil
+		}}
+		ac, err := NewArchivingClient(httpClient, dir)
+		assert.NoError(t, err)
+
+		resp, err := ac.Get("https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1")
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		archive, err := OpenArchive(dir)
+		assert.NoError(t, err)
+
+		resp, err = archive.Get("https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
+
+func TestArchive_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serve the latest archived response of a url", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		url := "https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1"
+		records := []ArchiveRecord{
+			{URL: url, FetchedAt: time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC), Status: http.StatusOK, Body: []byte("first crawl")},
+			{URL: url, FetchedAt: time.Date(2022, 3, 16, 8, 0, 0, 0, time.UTC), Status: http.StatusOK, Body: []byte("third crawl")},
+			{URL: url, FetchedAt: time.Date(2022, 3, 15, 8, 0, 0, 0, time.UTC), Status: http.StatusOK, Body: []byte("second crawl")},
+		}
+		for _, record := range records {
+			assert.NoError(t, writeArchiveRecord(dir, record))
+		}
+
+		archive, err := OpenArchive(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, archive.Len())
+
+		resp, err := archive.Get(url)
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "third crawl", string(body))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("serve records that keep the body in the header", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		url := "https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1"
+		f, err := os.Create(filepath.Join(dir, "20220314T080000.000000000-0123456789abcdef.json.gz"))
+		assert.NoError(t, err)
+		zw := gzip.NewWriter(f)
+		assert.NoError(t, json.NewEncoder(zw).Encode(ArchiveRecord{URL: url, Status: http.StatusOK, Body: []byte("first crawl")}))
+		assert.NoError(t, zw.Close())
+		assert.NoError(t, f.Close())
+
+		archive, err := OpenArchive(dir)
+		assert.NoError(t, err)
+		resp, err := archive.Get(url)
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "first crawl", string(body))
+	})
+
+	t.Run("return error for urls which are not archived", func(t *testing.T) {
+		t.Parallel()
+
+		archive, err := OpenArchive(t.TempDir())
+		assert.NoError(t, err)
+
+		_, err = archive.Get("https://www.thomann.de/de/linkshaender_modelle.html?ls=100&pg=1")
+		assert.Error(t, err)
+	})
+
+	t.Run("reparse archived listing pages without network access", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ac, err := NewArchivingClient(newTestHTTPClientForFixture("thomann_basses_six_strings.html"), dir)
+		assert.NoError(t, err)
+
+		crawled, err := LoadProducts(Thomann{http: ac})
+		assert.NoError(t, err)
+
+		archive, err := OpenArchive(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, len(Thomann{}.Categories()), archive.Len())
+
+		reparsed, err := LoadProducts(Thomann{http: archive})
+		assert.NoError(t, err)
+		assert.Equal(t, crawled, reparsed)
+	})
+}
+
+func readArchiveRecord(file string) (ArchiveRecord, error) {
+	record, body, err := openArchiveRecord(file)
+	if err != nil {
+		return ArchiveRecord{}, err
+	}
+	defer body.Close()
+
+	record.Body, err = ioutil.ReadAll(body)
+	return record, err
+}
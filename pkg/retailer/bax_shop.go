@@ -0,0 +1,182 @@
+package retailer
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+type BaxShopCategory struct {
+	Slug     string
+	Category string
+}
+
+type BaxShopStorefront struct {
+	Code       string
+	BaseURL    string
+	Filter     string
+	Categories []BaxShopCategory
+}
+
+var (
+	BaxShopNL = BaxShopStorefront{
+		Code:    "nl",
+		BaseURL: "https://www.bax-shop.nl",
+		Filter:  "linkshandig=ja",
+		Categories: []BaxShopCategory{
+			{Slug: "elektrische-gitaren", Category: "Linkshänder E-Gitarren"},
+			{Slug: "akoestische-gitaren", Category: "Linkshänder Akustikgitarren"},
+			{Slug: "klassieke-gitaren", Category: "Linkshänder Konzertgitarren"},
+			{Slug: "basgitaren", Category: "Linkshänder E-Bässe"},
+		},
+	}
+	BaxShopDE = BaxShopStorefront{
+		Code:    "de",
+		BaseURL: "https://www.bax-shop.de",
+		Filter:  "linkshandig=ja",
+		Categories: []BaxShopCategory{
+			{Slug: "e-gitarren", Category: "Linkshänder E-Gitarren"},
+			{Slug: "akustikgitarren", Category: "Linkshänder Akustikgitarren"},
+			{Slug: "konzertgitarren", Category: "Linkshänder Konzertgitarren"},
+			{Slug: "e-basse", Category: "Linkshänder E-Bässe"},
+		},
+	}
+)
+
+type BaxShop struct {
+	http        httpGetter
+	storefronts []BaxShopStorefront
+}
+
+func NewBaxShop(http httpGetter, storefronts ...BaxShopStorefront) BaxShop {
+	if len(storefronts) == 0 {
+		storefronts = []BaxShopStorefront{BaxShopNL, BaxShopDE}
+	}
+
+	return BaxShop{http: http, storefronts: storefronts}
+}
+
+func (b BaxShop) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	storefront, c, err := b.category(category)
+	if err != nil {
+		return ProductResponse{}, err
+	}
+
+	resp, err := b.http.Get(b.buildURL(storefront, c, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from bax-shop: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+
+	currentPage, lastPage := parseNumberedPagination(doc.Find(".pagination li"), "pagination__item--active")
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	productNodes := doc.Find(".product-list .product-block")
+	prds := make([]Product, len(productNodes.Nodes))
+	productNodes.Each(func(i int, s *goquery.Selection) {
+		p := b.parseProduct(s, storefront)
+		p.Category = c.Category
+		prds[i] = p
+	})
+
+	return ProductResponse{
+		Products:    prds,
+		CurrentPage: currentPage,
+		LastPage:    lastPage,
+	}, nil
+}
+
+func (b BaxShop) Name() string {
+	return "Bax-shop"
+}
+
+func (b BaxShop) Categories() []string {
+	categories := make([]string, 0)
+	for _, s := range b.storefronts {
+		for _, c := range s.Categories {
+			categories = append(categories, s.Code+":"+c.Slug)
+		}
+	}
+
+	return categories
+}
+
+func (b BaxShop) category(category string) (BaxShopStorefront, BaxShopCategory, error) {
+	parts := strings.SplitN(category, ":", 2)
+	if len(parts) == 2 {
+		for _, s := range b.storefronts {
+			if s.Code != parts[0] {
+				continue
+			}
+
+			for _, c := range s.Categories {
+				if c.Slug == parts[1] {
+					return s, c, nil
+				}
+			}
+		}
+	}
+
+	return BaxShopStorefront{}, BaxShopCategory{}, fmt.Errorf("unknown bax-shop category %s", category)
+}
+
+func (b BaxShop) PageURL(category string, options RequestOptions) string {
+	storefront, c, err := b.category(category)
+	if err != nil {
+		return ""
+	}
+
+	return b.buildURL(storefront, c, options)
+}
+
+func (b BaxShop) buildURL(storefront BaxShopStorefront, category BaxShopCategory, options RequestOptions) string {
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	return fmt.Sprintf("%s/%s?%s&page=%d", storefront.BaseURL, category.Slug, storefront.Filter, page)
+}
+
+func (b BaxShop) parseProduct(s *goquery.Selection, storefront BaxShopStorefront) Product {
+	title := s.Find(".product-block__title")
+	manufacturer := strings.TrimSpace(title.Find("strong").Text())
+	status := s.Find(".stock-status")
+	img := s.Find("img.product-block__image")
+
+	return Product{
+		Retailer:          b.Name(),
+		Storefront:        storefront.Code,
+		Manufacturer:      manufacturer,
+		Model:             strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(title.Text()), manufacturer)),
+		IsAvailable:       !status.HasClass("stock-status--out-of-stock"),
+		AvailabilityInfo:  strings.TrimSpace(status.Text()),
+		AvailabilityScore: b.parseAvailabilityScore(status),
+		Price:             parseLocalizedPrice(s.Find(".price").First().Text()),
+		Currency:          "EUR",
+		ProductURL:        storefront.BaseURL + s.Find("a.product-block__link").AttrOr("href", ""),
+		ThumbnailURL:      img.AttrOr("data-src", img.AttrOr("src", "")),
+	}
+}
+
+func (b BaxShop) parseAvailabilityScore(s *goquery.Selection) int {
+	if s.HasClass("stock-status--in-stock") {
+		return AvailabilityAvailable
+	}
+	if s.HasClass("stock-status--available-soon") {
+		return AvailabilityWithinDays
+	}
+	if s.HasClass("stock-status--backorder") {
+		return AvailabilityWithinWeeks
+	}
+
+	return AvailabilityUnknown
+}
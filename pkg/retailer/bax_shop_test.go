@@ -0,0 +1,174 @@
+package retailer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBaxShop_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse product from product page", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_nl_basses.html"))
+
+		response, err := bax.LoadProducts("nl:basgitaren", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 2)
+		assert.Equal(t, "Bax-shop", response.Products[0].Retailer)
+		assert.Equal(t, "nl", response.Products[0].Storefront)
+		assert.Equal(t, "Fender", response.Products[0].Manufacturer)
+		assert.Equal(t, "Player Jazz Bass LH PF 3-Color Sunburst", response.Products[0].Model)
+		assert.Equal(t, "Linkshänder E-Bässe", response.Products[0].Category)
+		assert.Equal(t, true, response.Products[0].IsAvailable)
+		assert.Equal(t, "Op voorraad", response.Products[0].AvailabilityInfo)
+		assert.Equal(t, float64(829), response.Products[0].Price)
+		assert.Equal(t, "EUR", response.Products[0].Currency)
+		assert.Equal(t, "https://www.bax-shop.nl/basgitaren/fender-player-jazz-bass-lh-pf-3-color-sunburst", response.Products[0].ProductURL)
+		assert.Equal(t, "https://static.bax-shop.nl/image/product/473950/1741410/77a1c0de/250x250/fender-player-jazz-bass-lh-pf-3-color-sunburst.jpg", response.Products[0].ThumbnailURL)
+	})
+
+	t.Run("map categories of each storefront onto our categories", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name             string
+			Fixture          string
+			Category         string
+			ExpectedCategory string
+		}{
+			{Name: "dutch storefront", Fixture: "baxshop_nl_basses.html", Category: "nl:basgitaren", ExpectedCategory: "Linkshänder E-Bässe"},
+			{Name: "german storefront", Fixture: "baxshop_de_electric_guitars.html", Category: "de:e-gitarren", ExpectedCategory: "Linkshänder E-Gitarren"},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				bax := NewBaxShop(newTestHTTPClientForFixture(tt.Fixture))
+
+				response, err := bax.LoadProducts(tt.Category, RequestOptions{})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedCategory, response.Products[0].Category)
+			})
+		}
+	})
+
+	t.Run("calculate availability score for products", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_de_electric_guitars.html"))
+
+		response, err := bax.LoadProducts("de:e-gitarren", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, AvailabilityAvailable, response.Products[0].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinDays, response.Products[1].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinWeeks, response.Products[2].AvailabilityScore)
+		assert.Equal(t, AvailabilityUnknown, response.Products[3].AvailabilityScore)
+		assert.Equal(t, false, response.Products[3].IsAvailable)
+	})
+
+	t.Run("parse prices with thousands separators", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_de_electric_guitars.html"))
+
+		response, err := bax.LoadProducts("de:e-gitarren", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(1199), response.Products[2].Price)
+	})
+
+	t.Run("parse pagination when there is only a single page", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_nl_basses.html"))
+
+		response, err := bax.LoadProducts("nl:basgitaren", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(1), response.LastPage)
+	})
+
+	t.Run("parse pagination when there are multiple pages", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_de_electric_guitars.html"))
+
+		response, err := bax.LoadProducts("de:e-gitarren", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(4), response.LastPage)
+	})
+
+	t.Run("use correct pagination query parameters depending on RequestOptions struct", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name              string
+			Page              uint
+			ExpectedURLSuffix string
+		}{
+			{
+				Name:              "zero-value RequestOptions",
+				Page:              0,
+				ExpectedURLSuffix: "?linkshandig=ja&page=1",
+			},
+			{
+				Name:              "valid RequestOptions",
+				Page:              2,
+				ExpectedURLSuffix: "?linkshandig=ja&page=2",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				httpSpy := testHTTPClient{
+					getFunc: func(url string) (*http.Response, error) {
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					},
+				}
+				bax := NewBaxShop(&httpSpy)
+
+				options := RequestOptions{Page: tt.Page}
+				_, _ = bax.LoadProducts("de:e-gitarren", options)
+
+				assert.Equal(t, tt.ExpectedURLSuffix, httpSpy.lastURL[strings.LastIndex(httpSpy.lastURL, "?"):])
+			})
+		}
+	})
+
+	t.Run("return error for unknown categories", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_nl_basses.html"), BaxShopDE)
+
+		_, err := bax.LoadProducts("nl:basgitaren", RequestOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		bax := NewBaxShop(newTestHTTPClientForFixture("baxshop_de_electric_guitars.html"))
+
+		options := RequestOptions{Page: 1337}
+		_, err := bax.LoadProducts("de:e-gitarren", options)
+
+		assert.Error(t, err)
+	})
+}
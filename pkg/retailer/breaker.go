@@ -0,0 +1,167 @@
+package retailer
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	BreakerClosed   string = "closed"
+	BreakerOpen            = "open"
+	BreakerHalfOpen        = "half_open"
+)
+
+type BreakerSettings struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+	HistorySize      int
+}
+
+var DefaultBreakerSettings = BreakerSettings{
+	FailureThreshold: 3,
+	Cooldown:         6 * time.Hour,
+	HistorySize:      10,
+}
+
+type Breaker struct {
+	State               string         `json:"state"`
+	ConsecutiveFailures int            `json:"consecutive_failures"`
+	OpenedAt            time.Time      `json:"opened_at"`
+	Outcomes            []CrawlOutcome `json:"outcomes"`
+}
+
+type CrawlOutcome struct {
+	Status    string    `json:"status"`
+	CrawledAt time.Time `json:"crawled_at"`
+}
+
+func (b Breaker) allow(settings BreakerSettings, now time.Time) (Breaker, bool) {
+	switch b.State {
+	case BreakerOpen:
+		if now.Sub(b.OpenedAt) < settings.Cooldown {
+			return b, false
+		}
+		b.State = BreakerHalfOpen
+		return b, true
+	default:
+		return b, true
+	}
+}
+
+func (b Breaker) record(settings BreakerSettings, status string, now time.Time) Breaker {
+	b.Outcomes = append(b.Outcomes, CrawlOutcome{Status: status, CrawledAt: now})
+	if len(b.Outcomes) > settings.HistorySize {
+		b.Outcomes = b.Outcomes[len(b.Outcomes)-settings.HistorySize:]
+	}
+
+	if status != CrawlStatusFailed {
+		b.State = BreakerClosed
+		b.ConsecutiveFailures = 0
+		return b
+	}
+
+	b.ConsecutiveFailures++
+	if b.State == BreakerHalfOpen || b.ConsecutiveFailures >= settings.FailureThreshold {
+		b.State = BreakerOpen
+		b.OpenedAt = now
+	}
+
+	return b
+}
+
+func (b Breaker) HealthScore() int {
+	if len(b.Outcomes) == 0 {
+		return 100
+	}
+
+	var score float64
+	for _, o := range b.Outcomes {
+		switch o.Status {
+		case CrawlStatusOK:
+			score += 1
+		case CrawlStatusStructureChanged:
+			score += 0.5
+		}
+	}
+
+	return int(math.Round(100 * score / float64(len(b.Outcomes))))
+}
+
+func (h *HealthMonitor) SetBreakerSettings(settings BreakerSettings) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.breakerSettings = settings
+}
+
+func (h *HealthMonitor) Breaker(retailer string) Breaker {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.breaker(retailer)
+}
+
+func (h *HealthMonitor) allow(retailer string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, ok := h.breaker(retailer).allow(h.breakerSettings, h.now())
+	h.breakers[retailer] = b
+
+	return ok
+}
+
+func (h *HealthMonitor) breaker(retailer string) Breaker {
+	b, ok := h.breakers[retailer]
+	if !ok {
+		b = Breaker{State: BreakerClosed}
+	}
+
+	return b
+}
+
+type healthState struct {
+	Reports   map[string]CrawlReport `json:"reports"`
+	Baselines map[string]CrawlStats  `json:"baselines"`
+	Held      map[string]heldCrawl   `json:"held"`
+	Breakers  map[string]Breaker     `json:"breakers"`
+}
+
+func (h *HealthMonitor) Dump(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(healthState{Reports: h.reports, Baselines: h.baselines, Held: h.held, Breakers: h.breakers})
+}
+
+func (h *HealthMonitor) Load(r io.Reader) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state := healthState{
+		Reports:   make(map[string]CrawlReport),
+		Baselines: make(map[string]CrawlStats),
+		Held:      make(map[string]heldCrawl),
+		Breakers:  make(map[string]Breaker),
+	}
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+
+	if state.Reports != nil {
+		h.reports = state.Reports
+	}
+	if state.Baselines != nil {
+		h.baselines = state.Baselines
+	}
+	if state.Held != nil {
+		h.held = state.Held
+	}
+	if state.Breakers != nil {
+		h.breakers = state.Breakers
+	}
+
+	return nil
+}
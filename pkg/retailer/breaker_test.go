@@ -0,0 +1,183 @@
+package retailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitor_CircuitBreaker(t *testing.T) {
+	now := time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC)
+
+	failing := true
+	calls := 0
+	r := stubRetailer{}
+	r.CategoriesFunc = func() []string { return []string{"guitars"} }
+	r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+		calls++
+		if failing {
+			return ProductResponse{}, errors.New("503 Service Unavailable")
+		}
+		p := Product{Retailer: "Stub", Manufacturer: "Fender", Model: "Player Stratocaster LH", Price: 799, ProductURL: "https://example.com"}
+		return ProductResponse{Products: []Product{p}, CurrentPage: 1, LastPage: 1}, nil
+	}
+
+	store := &testProductStore{}
+	hm := NewHealthMonitor(DefaultHealthThresholds)
+	hm.SetBreakerSettings(BreakerSettings{FailureThreshold: 3, Cooldown: time.Hour, HistorySize: 4})
+	hm.now = func() time.Time { return now }
+
+	t.Run("open breaker after consecutive failures", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_ = UpdateRetailersWithHealthCheck(store, hm, r)
+		}
+
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, BreakerOpen, hm.Breaker("Stub").State)
+		assert.Equal(t, now, hm.Breaker("Stub").OpenedAt)
+	})
+
+	t.Run("skip retailer while breaker is open", func(t *testing.T) {
+		now = now.Add(30 * time.Minute)
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, CrawlStatusSkipped, hm.Reports()[0].Status)
+		assert.Equal(t, BreakerOpen, hm.Reports()[0].Breaker)
+	})
+
+	t.Run("open breaker again if probe fails", func(t *testing.T) {
+		now = now.Add(31 * time.Minute)
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, BreakerOpen, hm.Breaker("Stub").State)
+		assert.Equal(t, now, hm.Breaker("Stub").OpenedAt)
+	})
+
+	t.Run("close breaker if probe succeeds", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		failing = false
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+		assert.Equal(t, 5, calls)
+		assert.Equal(t, BreakerClosed, hm.Breaker("Stub").State)
+		assert.Equal(t, 0, hm.Breaker("Stub").ConsecutiveFailures)
+		assert.Len(t, store.Products, 1)
+	})
+
+	t.Run("compute health score from recent outcomes", func(t *testing.T) {
+		assert.Len(t, hm.Breaker("Stub").Outcomes, 4)
+		assert.Equal(t, 25, hm.Reports()[0].HealthScore)
+	})
+}
+
+func TestBreaker_HealthScore(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Outcomes []string
+		Expected int
+	}{
+		{Name: "no crawls yet", Outcomes: nil, Expected: 100},
+		{Name: "only successful crawls", Outcomes: []string{CrawlStatusOK, CrawlStatusOK}, Expected: 100},
+		{Name: "only failed crawls", Outcomes: []string{CrawlStatusFailed, CrawlStatusFailed}, Expected: 0},
+		{Name: "mixed crawls", Outcomes: []string{CrawlStatusOK, CrawlStatusStructureChanged, CrawlStatusFailed}, Expected: 50},
+	}
+
+	for _, tt := range tests {
+		b := Breaker{}
+		for _, o := range tt.Outcomes {
+			b.Outcomes = append(b.Outcomes, CrawlOutcome{Status: o})
+		}
+
+		assert.Equal(t, tt.Expected, b.HealthScore(), tt.Name)
+	}
+}
+
+func TestHealthMonitor_Dump(t *testing.T) {
+	r := stubRetailer{}
+	r.CategoriesFunc = func() []string { return []string{"guitars"} }
+	r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+		return ProductResponse{}, errors.New("503 Service Unavailable")
+	}
+
+	store := &testProductStore{}
+	hm := NewHealthMonitor(DefaultHealthThresholds)
+	hm.now = func() time.Time { return time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC) }
+	_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+	var buf bytes.Buffer
+	assert.NoError(t, hm.Dump(&buf))
+
+	restored := NewHealthMonitor(DefaultHealthThresholds)
+	assert.NoError(t, restored.Load(&buf))
+
+	assert.Equal(t, hm.Reports(), restored.Reports())
+	assert.Equal(t, 1, restored.Breaker("Stub").ConsecutiveFailures)
+}
+
+func TestHealthMonitor_Load(t *testing.T) {
+	t.Run("compare with the last crawl before the restart", func(t *testing.T) {
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+		n := 10
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			prds := make([]Product, n)
+			for i := range prds {
+				prds[i] = Product{Retailer: "Stub", Manufacturer: "Fender", Model: fmt.Sprintf("Player Stratocaster LH %d", i), Price: 799, ProductURL: "https://example.com"}
+			}
+			return ProductResponse{Products: prds, CurrentPage: 1, LastPage: 1}, nil
+		}
+
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+		var buf bytes.Buffer
+		assert.NoError(t, hm.Dump(&buf))
+
+		restored := NewHealthMonitor(DefaultHealthThresholds)
+		assert.NoError(t, restored.Load(&buf))
+
+		n = 2
+		_ = UpdateRetailersWithHealthCheck(store, restored, r)
+		assert.Len(t, restored.Alerts(), 1)
+	})
+
+	t.Run("keep structure alerts and held products after a restart", func(t *testing.T) {
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+		n := 10
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			prds := make([]Product, n)
+			for i := range prds {
+				prds[i] = Product{Retailer: "Stub", Manufacturer: "Fender", Model: fmt.Sprintf("Player Stratocaster LH %d", i), Price: 799, ProductURL: "https://example.com"}
+			}
+			return ProductResponse{Products: prds, CurrentPage: 1, LastPage: 1}, nil
+		}
+
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		hm.now = func() time.Time { return time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC) }
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+		n = 2
+		_ = UpdateRetailersWithHealthCheck(store, hm, r)
+
+		var buf bytes.Buffer
+		assert.NoError(t, hm.Dump(&buf))
+
+		restored := NewHealthMonitor(DefaultHealthThresholds)
+		assert.NoError(t, restored.Load(&buf))
+		assert.Equal(t, hm.Alerts(), restored.Alerts())
+
+		n = 3
+		_ = UpdateRetailersWithHealthCheck(store, restored, r)
+		assert.Equal(t, 10, restored.Alerts()[0].Previous.Products)
+
+		assert.NoError(t, restored.Confirm(store, "Stub"))
+		assert.Len(t, store.Products, 3)
+	})
+}
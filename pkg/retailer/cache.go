@@ -0,0 +1,378 @@
+package retailer
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type CacheStats struct {
+	Requests      int `json:"requests"`
+	Hits          int `json:"hits"`
+	ParsesSkipped int `json:"parses_skipped"`
+	Uncached      int `json:"uncached"`
+}
+
+func (s CacheStats) HitRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Requests)
+}
+
+type CachingClient struct {
+	http httpDoer
+	dir  string
+	now  func() time.Time
+
+	mu    sync.Mutex
+	fresh map[string]freshResponse
+	stats map[string]CacheStats
+}
+
+type freshResponse struct {
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (f freshResponse) response() *http.Response {
+	return &http.Response{
+		Status:     f.status,
+		StatusCode: f.statusCode,
+		Header:     f.header,
+		Body:       ioutil.NopCloser(bytes.NewReader(f.body)),
+	}
+}
+
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+func NewCachingClient(http httpDoer, dir string) (*CachingClient, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	return &CachingClient{
+		http:  http,
+		dir:   dir,
+		now:   time.Now,
+		fresh: make(map[string]freshResponse),
+		stats: make(map[string]CacheStats),
+	}, nil
+}
+
+func (c *CachingClient) Get(url string) (*http.Response, error) {
+	c.mu.Lock()
+	f, fresh := c.fresh[url]
+	delete(c.fresh, url)
+	c.mu.Unlock()
+
+	if fresh {
+		return f.response(), nil
+	}
+
+	resp, _, err := c.fetch(url, "")
+	return resp, err
+}
+
+func (c *CachingClient) Stats(retailer string) CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats[retailer]
+}
+
+func (c *CachingClient) revalidate(url string, retailer string) (bool, error) {
+	resp, notModified, err := c.fetch(url, retailer)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.fresh[url] = freshResponse{status: resp.Status, statusCode: resp.StatusCode, header: resp.Header, body: body}
+	c.mu.Unlock()
+
+	return notModified, nil
+}
+
+func (c *CachingClient) fetch(url string, retailer string) (*http.Response, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry, cached := c.entry(url)
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	notModified := cached && resp.StatusCode == http.StatusNotModified
+	c.count(retailer, notModified)
+
+	if notModified {
+		resp.Body.Close()
+
+		body, err := ioutil.ReadFile(c.path(url, ".body"))
+		if err != nil {
+			return nil, false, fmt.Errorf("could not read cached body of %s: %w", url, err)
+		}
+
+		return cachedResponse(body), true, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, false, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read response body of %s: %w", url, err)
+	}
+
+	entry = cacheEntry{URL: url, ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), StoredAt: c.now()}
+	if err = c.store(entry, body); err != nil {
+		return nil, false, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, false, nil
+}
+
+func (c *CachingClient) count(retailer string, hit bool) {
+	if retailer == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats[retailer]
+	s.Requests++
+	if hit {
+		s.Hits++
+	}
+	c.stats[retailer] = s
+}
+
+func (c *CachingClient) uncached(retailer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats[retailer]
+	s.Uncached++
+	c.stats[retailer] = s
+}
+
+func (c *CachingClient) skippedParse(retailer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats[retailer]
+	s.ParsesSkipped++
+	c.stats[retailer] = s
+}
+
+func (c *CachingClient) discard(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.fresh, url)
+}
+
+func (c *CachingClient) entry(url string) (cacheEntry, bool) {
+	data, err := ioutil.ReadFile(c.path(url, ".json"))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (c *CachingClient) store(entry cacheEntry, body []byte) error {
+	_ = os.Remove(c.path(entry.URL, ".parsed.json"))
+	if entry.ETag == "" && entry.LastModified == "" {
+		_ = os.Remove(c.path(entry.URL, ".json"))
+		_ = os.Remove(c.path(entry.URL, ".body"))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(c.path(entry.URL, ".body"), body, 0644); err != nil {
+		return fmt.Errorf("could not cache body of %s: %w", entry.URL, err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(c.path(entry.URL, ".json"), data, 0644); err != nil {
+		return fmt.Errorf("could not cache %s: %w", entry.URL, err)
+	}
+
+	return nil
+}
+
+func (c *CachingClient) parsed(url string) (ProductResponse, bool) {
+	data, err := ioutil.ReadFile(c.path(url, ".parsed.json"))
+	if err != nil {
+		return ProductResponse{}, false
+	}
+
+	var resp ProductResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		return ProductResponse{}, false
+	}
+
+	return resp, true
+}
+
+func (c *CachingClient) storeParsed(url string, resp ProductResponse) error {
+	if _, ok := c.entry(url); !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path(url, ".parsed.json"), data, 0644)
+}
+
+func (c *CachingClient) path(url string, suffix string) string {
+	hash := sha1.Sum([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+suffix)
+}
+
+func cachedResponse(body []byte) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"X-Cache": []string{"HIT"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+type wrappedRetailer interface {
+	Unwrap() Retailer
+}
+
+func unwrapRetailer(r Retailer) Retailer {
+	for {
+		w, ok := r.(wrappedRetailer)
+		if !ok {
+			return r
+		}
+		r = w.Unwrap()
+	}
+}
+
+func retailerCacheStats(r Retailer) (CacheStats, bool) {
+	for {
+		if c, ok := r.(cachedRetailer); ok {
+			return c.CacheStats(), true
+		}
+
+		w, ok := r.(wrappedRetailer)
+		if !ok {
+			return CacheStats{}, false
+		}
+		r = w.Unwrap()
+	}
+}
+
+type PageURLBuilder interface {
+	PageURL(category string, options RequestOptions) string
+}
+
+type cachedRetailer struct {
+	Retailer
+	cache *CachingClient
+}
+
+func WithCache(r Retailer, c *CachingClient) Retailer {
+	return cachedRetailer{Retailer: r, cache: c}
+}
+
+func (c cachedRetailer) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	var url string
+	if ub, ok := unwrapRetailer(c.Retailer).(PageURLBuilder); ok {
+		url = ub.PageURL(category, options)
+	}
+	if url == "" {
+		c.cache.uncached(c.Name())
+		return c.Retailer.LoadProducts(category, options)
+	}
+
+	notModified, err := c.cache.revalidate(url, c.Name())
+	if err != nil {
+		return ProductResponse{}, err
+	}
+	defer c.cache.discard(url)
+
+	if notModified {
+		if resp, ok := c.cache.parsed(url); ok {
+			c.cache.skippedParse(c.Name())
+			return resp, nil
+		}
+	}
+
+	resp, err := c.Retailer.LoadProducts(category, options)
+	if err != nil {
+		return ProductResponse{}, err
+	}
+
+	if err = c.cache.storeParsed(url, resp); err != nil {
+		return ProductResponse{}, err
+	}
+
+	return resp, nil
+}
+
+func (c cachedRetailer) CacheStats() CacheStats {
+	return c.cache.Stats(c.Name())
+}
+
+func (c cachedRetailer) Unwrap() Retailer {
+	return c.Retailer
+}
@@ -0,0 +1,254 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachingClient_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("send conditional requests and reuse cached body when not modified", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+
+		first, err := cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		assert.NoError(t, err)
+		firstBody, _ := ioutil.ReadAll(first.Body)
+
+		second, err := cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		assert.NoError(t, err)
+		secondBody, _ := ioutil.ReadAll(second.Body)
+
+		assert.Equal(t, http.StatusOK, second.StatusCode)
+		assert.Equal(t, firstBody, secondBody)
+		assert.Equal(t, []string{"", `"v1"`}, shop.ifNoneMatch)
+		assert.Equal(t, 1, shop.fullResponses)
+	})
+
+	t.Run("use last-modified for conditional requests", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", "", "Mon, 14 Mar 2022 08:00:00 GMT")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+
+		_, _ = cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		_, _ = cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+
+		assert.Equal(t, []string{"", "Mon, 14 Mar 2022 08:00:00 GMT"}, shop.ifModifiedSince)
+		assert.Equal(t, 1, shop.fullResponses)
+	})
+
+	t.Run("do not cache responses without validators", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", "", "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+
+		_, _ = cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		_, _ = cc.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+
+		assert.Equal(t, 2, shop.fullResponses)
+	})
+
+	t.Run("remove cached body once a page loses its validators", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+		url := "https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1"
+
+		_, _ = cc.Get(url)
+		assert.FileExists(t, cc.path(url, ".body"))
+
+		shop.etag = ""
+		_, _ = cc.Get(url)
+		assert.NoFileExists(t, cc.path(url, ".json"))
+		assert.NoFileExists(t, cc.path(url, ".body"))
+	})
+}
+
+func TestWithCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skip parsing of unmodified pages", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+		tho := WithCache(Thomann{http: cc}, cc)
+
+		first, err := tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+		second, err := tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, shop.fullResponses)
+		assert.Equal(t, CacheStats{Requests: 2, Hits: 1, ParsesSkipped: 1}, cc.Stats("Thomann"))
+		assert.Equal(t, 0.5, cc.Stats("Thomann").HitRate())
+	})
+
+	t.Run("parse pages again once they changed", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+		tho := WithCache(Thomann{http: cc}, cc)
+
+		_, err = tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+
+		shop.fixture, shop.etag = "thomann_basses_four_strings_second_page.html", `"v2"`
+		resp, err := tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+
+		expected, err := Thomann{http: newTestHTTPClientForFixture("thomann_basses_four_strings_second_page.html")}.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resp)
+		assert.Equal(t, 2, shop.fullResponses)
+		assert.Equal(t, CacheStats{Requests: 2}, cc.Stats("Thomann"))
+	})
+
+	t.Run("build page urls for every builtin retailer", func(t *testing.T) {
+		t.Parallel()
+
+		for _, info := range BuiltinRetailers() {
+			r, err := NewBuiltinRetailer(info.Name, &testHTTPClient{})
+			assert.NoError(t, err)
+
+			ub, ok := r.(PageURLBuilder)
+			assert.True(t, ok, info.Name)
+			if ok {
+				assert.NotEmpty(t, ub.PageURL(r.Categories()[0], RequestOptions{}), info.Name)
+			}
+		}
+	})
+
+	t.Run("count pages that bypass the cache", func(t *testing.T) {
+		t.Parallel()
+
+		cc, err := NewCachingClient(newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, ""), t.TempDir())
+		assert.NoError(t, err)
+
+		r := stubRetailer{}
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			return ProductResponse{CurrentPage: 1, LastPage: 1}, nil
+		}
+
+		_, err = WithCache(r, cc).LoadProducts("guitars", RequestOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, CacheStats{Uncached: 1}, cc.Stats(r.Name()))
+	})
+
+	t.Run("reuse failed revalidation responses", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &testUnavailableShop{}
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+		tho := WithCache(Thomann{http: cc}, cc)
+
+		_, _ = tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.Equal(t, 1, shop.requests)
+	})
+
+	t.Run("forget revalidated pages the retailer fetched under another url", func(t *testing.T) {
+		t.Parallel()
+
+		cc, err := NewCachingClient(newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, ""), t.TempDir())
+		assert.NoError(t, err)
+		tho := WithCache(Thomann{http: newTestHTTPClientForFixture("thomann_basses_six_strings.html")}, cc)
+
+		_, err = tho.LoadProducts("6_saitige_linkshaender_e-baesse.html", RequestOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, cc.fresh)
+	})
+
+	t.Run("add cache stats to crawl report", func(t *testing.T) {
+		t.Parallel()
+
+		shop := newTestConditionalShop("thomann_basses_six_strings.html", `"v1"`, "")
+		cc, err := NewCachingClient(shop, t.TempDir())
+		assert.NoError(t, err)
+
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		cp, err := OpenCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), 0)
+		assert.NoError(t, err)
+
+		err = UpdateRetailersWithHealthCheck(store, hm, WithCheckpoint(WithCache(Thomann{http: cc}, cc), cp))
+		assert.NoError(t, err)
+
+		reports := hm.Reports()
+		assert.Len(t, reports, 1)
+		assert.Equal(t, &CacheStats{Requests: len(Thomann{}.Categories())}, reports[0].Cache)
+	})
+}
+
+type testUnavailableShop struct {
+	requests int
+}
+
+func (s *testUnavailableShop) Do(req *http.Request) (*http.Response, error) {
+	s.requests++
+
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = rec.WriteString("maintenance")
+
+	return rec.Result(), nil
+}
+
+type testConditionalShop struct {
+	fixture         string
+	etag            string
+	lastModified    string
+	fullResponses   int
+	ifNoneMatch     []string
+	ifModifiedSince []string
+}
+
+func newTestConditionalShop(fixture, etag, lastModified string) *testConditionalShop {
+	return &testConditionalShop{fixture: fixture, etag: etag, lastModified: lastModified}
+}
+
+func (s *testConditionalShop) Do(req *http.Request) (*http.Response, error) {
+	s.ifNoneMatch = append(s.ifNoneMatch, req.Header.Get("If-None-Match"))
+	s.ifModifiedSince = append(s.ifModifiedSince, req.Header.Get("If-Modified-Since"))
+
+	rec := httptest.NewRecorder()
+	if (s.etag != "" && req.Header.Get("If-None-Match") == s.etag) || (s.lastModified != "" && req.Header.Get("If-Modified-Since") == s.lastModified) {
+		rec.WriteHeader(http.StatusNotModified)
+		return rec.Result(), nil
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join("testdata", s.fixture))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.etag != "" {
+		rec.Header().Set("ETag", s.etag)
+	}
+	if s.lastModified != "" {
+		rec.Header().Set("Last-Modified", s.lastModified)
+	}
+	rec.WriteHeader(http.StatusOK)
+	_, _ = rec.Write(body)
+	s.fullResponses++
+
+	return rec.Result(), nil
+}
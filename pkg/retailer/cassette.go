@@ -0,0 +1,172 @@
+package retailer
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	CassetteRecord string = "record"
+	CassetteReplay        = "replay"
+)
+
+const cassetteIndex = "index.json"
+
+var cassetteFileName = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+type Cassette struct {
+	http  httpGetter
+	dir   string
+	mode  string
+	mu    sync.Mutex
+	index map[string]CassetteEntry
+}
+
+type CassetteEntry struct {
+	File        string `json:"file"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+func NewCassette(http httpGetter, dir string, mode string) (*Cassette, error) {
+	if mode != CassetteRecord && mode != CassetteReplay {
+		return nil, fmt.Errorf("unknown cassette mode %s", mode)
+	}
+
+	c := &Cassette{http: http, dir: dir, mode: mode, index: make(map[string]CassetteEntry)}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, cassetteIndex))
+	if err != nil && !(errors.Is(err, os.ErrNotExist) && mode == CassetteRecord) {
+		return nil, fmt.Errorf("could not read cassette index: %w", err)
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, &c.index); err != nil {
+			return nil, fmt.Errorf("could not decode cassette index: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Cassette) Get(url string) (*http.Response, error) {
+	if c.mode == CassetteRecord {
+		return c.record(url)
+	}
+
+	return c.replay(url)
+}
+
+func (c *Cassette) URLs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	urls := make([]string, 0, len(c.index))
+	for u := range c.index {
+		urls = append(urls, u)
+	}
+
+	return urls
+}
+
+func (c *Cassette) record(url string) (*http.Response, error) {
+	resp, err := c.http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body of %s: %w", url, err)
+	}
+
+	entry := CassetteEntry{File: cassetteFile(url), Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create cassette directory: %w", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(c.dir, entry.File), body, 0644); err != nil {
+		return nil, fmt.Errorf("could not record %s: %w", url, err)
+	}
+
+	c.index[url] = entry
+	if err = c.writeIndex(); err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (c *Cassette) replay(url string) (*http.Response, error) {
+	c.mu.Lock()
+	entry, ok := c.index[url]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s is not recorded in cassette %s", url, c.dir)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(c.dir, entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("could not replay %s: %w", url, err)
+	}
+
+	header := http.Header{}
+	if entry.ContentType != "" {
+		header.Set("Content-Type", entry.ContentType)
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode: entry.Status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func (c *Cassette) writeIndex() error {
+	data, err := json.MarshalIndent(c.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode cassette index: %w", err)
+	}
+
+	tmp := filepath.Join(c.dir, cassetteIndex+".tmp")
+	if err = ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("could not write cassette index: %w", err)
+	}
+
+	return os.Rename(tmp, filepath.Join(c.dir, cassetteIndex))
+}
+
+func cassetteFile(rawURL string) string {
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = u.Host + u.Path
+		if u.RawQuery != "" {
+			name += "_" + u.RawQuery
+		}
+	}
+
+	name = strings.Trim(cassetteFileName.ReplaceAllString(name, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	hash := sha1.Sum([]byte(rawURL))
+	return fmt.Sprintf("%s_%s", name, hex.EncodeToString(hash[:4]))
+}
@@ -0,0 +1,99 @@
+package retailer
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestCassette_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("record raw responses keyed by url", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		cassette, err := NewCassette(newTestHTTPClientForFixture("musikproduktiv_guitars_last_page.html"), dir, CassetteRecord)
+		assert.NoError(t, err)
+
+		_, err = cassette.Get("https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=3")
+		assert.NoError(t, err)
+
+		fixture, err := ioutil.ReadFile(filepath.Join("testdata", "musikproduktiv_guitars_last_page.html"))
+		assert.NoError(t, err)
+
+		recorded, err := ioutil.ReadFile(filepath.Join(dir, cassetteFile("https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=3")))
+		assert.NoError(t, err)
+		assert.Equal(t, fixture, recorded)
+
+		assert.FileExists(t, filepath.Join(dir, "index.json"))
+		assert.Equal(t, []string{"https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=3"}, cassette.URLs())
+	})
+
+	t.Run("replay recorded responses offline", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		recorder, err := NewCassette(newTestHTTPClientForFixture("thomann_basses_six_strings.html"), dir, CassetteRecord)
+		assert.NoError(t, err)
+
+		recorded, err := LoadProducts(Thomann{http: recorder})
+		assert.NoError(t, err)
+
+		offline := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return nil, errors.New("network access during replay")
+		}}
+		player, err := NewCassette(offline, dir, CassetteReplay)
+		assert.NoError(t, err)
+
+		store := &testProductStore{}
+		err = UpdateRetailers(store, Thomann{http: player})
+		assert.NoError(t, err)
+		assert.Equal(t, recorded, store.Products)
+	})
+
+	t.Run("return error for urls which are not recorded", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		recorder, err := NewCassette(newTestHTTPClientForFixture("thomann_basses_six_strings.html"), dir, CassetteRecord)
+		assert.NoError(t, err)
+		_, err = recorder.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=1")
+		assert.NoError(t, err)
+
+		player, err := NewCassette(nil, dir, CassetteReplay)
+		assert.NoError(t, err)
+
+		_, err = player.Get("https://www.thomann.de/de/6_saitige_linkshaender_e-baesse.html?ls=100&pg=2")
+		assert.Error(t, err)
+	})
+}
+
+func TestNewCassette(t *testing.T) {
+	_, err := NewCassette(nil, t.TempDir(), "rewind")
+	assert.Error(t, err)
+
+	_, err = NewCassette(nil, t.TempDir(), CassetteReplay)
+	assert.Error(t, err)
+
+	_, err = NewCassette(nil, t.TempDir(), CassetteRecord)
+	assert.NoError(t, err)
+}
+
+func TestCassetteFile(t *testing.T) {
+	tests := []struct {
+		URL          string
+		ExpectedName string
+	}{
+		{URL: "https://www.thomann.de/de/linkshaender_modelle.html?ls=100&pg=1", ExpectedName: "www.thomann.de_de_linkshaender_modelle.html_ls_100_pg_1_"},
+		{URL: "https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=2", ExpectedName: "www.musik-produktiv.de_e-gitarre-linkshaender_p_2_"},
+	}
+
+	for _, tt := range tests {
+		assert.Regexp(t, "^"+regexp.QuoteMeta(tt.ExpectedName)+"[0-9a-f]{8}$", cassetteFile(tt.URL))
+	}
+}
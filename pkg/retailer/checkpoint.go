@@ -0,0 +1,266 @@
+package retailer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type Checkpoint struct {
+	path string
+
+	mu        sync.Mutex
+	startedAt time.Time
+	written   bool
+	units     map[checkpointKey]CheckpointUnit
+	crawled   map[string]bool
+}
+
+type checkpointEntry struct {
+	StartedAt *time.Time      `json:"started_at,omitempty"`
+	Unit      *CheckpointUnit `json:"unit,omitempty"`
+	Crawled   string          `json:"crawled,omitempty"`
+}
+
+type CheckpointUnit struct {
+	Retailer string     `json:"retailer"`
+	Category string     `json:"category"`
+	Page     uint       `json:"page"`
+	LastPage uint       `json:"last_page"`
+	Stats    CrawlStats `json:"stats"`
+}
+
+type checkpointKey struct {
+	retailer string
+	category string
+	page     uint
+}
+
+func OpenCheckpoint(path string, maxAge time.Duration) (*Checkpoint, error) {
+	return openCheckpoint(path, maxAge, time.Now())
+}
+
+func openCheckpoint(path string, maxAge time.Duration, now time.Time) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, startedAt: now, units: make(map[checkpointKey]CheckpointUnit), crawled: make(map[string]bool)}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read checkpoint: %w", err)
+	}
+	defer f.Close()
+
+	var offset int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read checkpoint: %w", err)
+		}
+
+		var entry checkpointEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			break
+		}
+		if offset == 0 {
+			if entry.StartedAt == nil {
+				return nil, errors.New("could not decode checkpoint: missing start time")
+			}
+			c.startedAt = *entry.StartedAt
+		}
+		c.apply(entry)
+		offset += int64(len(line))
+	}
+	if offset == 0 {
+		return nil, errors.New("could not decode checkpoint: missing start time")
+	}
+
+	if now.Sub(c.startedAt) > maxAge {
+		c.startedAt = now
+		c.units = make(map[checkpointKey]CheckpointUnit)
+		c.crawled = make(map[string]bool)
+		return c, c.Remove()
+	}
+
+	if err = f.Truncate(offset); err != nil {
+		return nil, fmt.Errorf("could not repair checkpoint: %w", err)
+	}
+	c.written = true
+
+	return c, nil
+}
+
+func (c *Checkpoint) apply(entry checkpointEntry) {
+	if entry.Unit != nil {
+		c.units[checkpointKey{entry.Unit.Retailer, entry.Unit.Category, entry.Unit.Page}] = *entry.Unit
+	}
+	if entry.Crawled != "" {
+		c.crawled[entry.Crawled] = true
+	}
+}
+
+func (c *Checkpoint) StartedAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.startedAt
+}
+
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.units)
+}
+
+func (c *Checkpoint) Unit(retailer, category string, page uint) (CheckpointUnit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unit, ok := c.units[checkpointKey{retailer, category, normalizePage(page)}]
+	return unit, ok
+}
+
+func (c *Checkpoint) Complete(unit CheckpointUnit) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unit.Page = normalizePage(unit.Page)
+	if err := c.append(checkpointEntry{Unit: &unit}); err != nil {
+		return err
+	}
+	c.apply(checkpointEntry{Unit: &unit})
+
+	return nil
+}
+
+func (c *Checkpoint) Stats(retailer string) CrawlStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stats CrawlStats
+	for _, unit := range c.units {
+		if unit.Retailer == retailer {
+			stats = stats.add(unit.Stats)
+		}
+	}
+
+	return stats
+}
+
+func (c *Checkpoint) Crawled(retailer string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.crawled[retailer]
+}
+
+func (c *Checkpoint) MarkCrawled(retailer string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.append(checkpointEntry{Crawled: retailer}); err != nil {
+		return err
+	}
+	c.crawled[retailer] = true
+
+	return nil
+}
+
+func (c *Checkpoint) append(entry checkpointEntry) error {
+	entries := []checkpointEntry{entry}
+	if !c.written {
+		startedAt := c.startedAt
+		entries = []checkpointEntry{{StartedAt: &startedAt}, entry}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("could not encode checkpoint: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write checkpoint: %w", err)
+	}
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write checkpoint: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("could not write checkpoint: %w", err)
+	}
+	c.written = true
+
+	return nil
+}
+
+func (c *Checkpoint) Remove() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := os.Remove(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove checkpoint: %w", err)
+	}
+	c.written = false
+
+	return nil
+}
+
+type checkpointedRetailer struct {
+	Retailer
+	checkpoint *Checkpoint
+}
+
+func WithCheckpoint(r Retailer, c *Checkpoint) Retailer {
+	return checkpointedRetailer{Retailer: r, checkpoint: c}
+}
+
+func (c checkpointedRetailer) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	if unit, ok := c.checkpoint.Unit(c.Name(), category, options.Page); ok {
+		return ProductResponse{CurrentPage: unit.Page, LastPage: unit.LastPage}, nil
+	}
+
+	return c.Retailer.LoadProducts(category, options)
+}
+
+func (c checkpointedRetailer) Unwrap() Retailer {
+	return c.Retailer
+}
+
+func retailerCheckpoint(r Retailer) (*Checkpoint, bool) {
+	for {
+		if c, ok := r.(checkpointedRetailer); ok {
+			return c.checkpoint, true
+		}
+
+		w, ok := r.(wrappedRetailer)
+		if !ok {
+			return nil, false
+		}
+		r = w.Unwrap()
+	}
+}
+
+func normalizePage(page uint) uint {
+	if page == 0 {
+		return 1
+	}
+
+	return page
+}
@@ -0,0 +1,231 @@
+package retailer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	newPagedRetailer := func(requested *[]string, failOn string, unpriced ...string) stubRetailer {
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars", "basses"} }
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			unit := fmt.Sprintf("%s/%d", category, options.Page)
+			*requested = append(*requested, unit)
+			if unit == failOn {
+				return ProductResponse{}, errors.New("connection reset by peer")
+			}
+
+			p := Product{Retailer: "Stub", Manufacturer: "Fender", Model: unit, Price: 799, ProductURL: "https://example.com"}
+			for _, u := range unpriced {
+				if u == unit {
+					p.Price = 0
+				}
+			}
+			return ProductResponse{Products: []Product{p}, CurrentPage: options.Page, LastPage: 2}, nil
+		}
+		return r
+	}
+
+	t.Run("store each page and resume with the remaining pages", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		cp, err := OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+
+		var requested []string
+		store := &recordingStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		assert.NoError(t, UpdateRetailersWithHealthCheck(store, hm, WithCheckpoint(newPagedRetailer(&requested, "basses/2"), cp)))
+		assert.Equal(t, []string{"guitars/1", "guitars/2", "basses/1", "basses/2"}, requested)
+		assert.Equal(t, []string{"guitars/1", "guitars/2", "basses/1"}, modelsOf(store.Upserted))
+
+		cp, err = OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cp.Len())
+
+		requested = nil
+		store = &recordingStore{}
+		assert.NoError(t, UpdateRetailersWithHealthCheck(store, hm, WithCheckpoint(newPagedRetailer(&requested, ""), cp)))
+		assert.Equal(t, []string{"basses/2"}, requested)
+		assert.Equal(t, []string{"basses/2"}, modelsOf(store.Upserted))
+		assert.Equal(t, 4, hm.Reports()[0].Stats.Products)
+		assert.True(t, cp.Crawled("Stub"))
+	})
+
+	t.Run("hold back pages from the first page without prices", func(t *testing.T) {
+		t.Parallel()
+
+		cp, err := OpenCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), time.Hour)
+		assert.NoError(t, err)
+
+		var requested []string
+		store := &recordingStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		assert.NoError(t, UpdateRetailersWithHealthCheck(store, hm, WithCheckpoint(newPagedRetailer(&requested, "", "guitars/2", "basses/1"), cp)))
+
+		assert.Equal(t, []string{"guitars/1"}, modelsOf(store.Upserted))
+		assert.Len(t, hm.Alerts(), 1)
+		assert.Equal(t, 1, cp.Len())
+		assert.False(t, cp.Crawled("Stub"))
+
+		assert.NoError(t, hm.Confirm(store, "Stub"))
+		assert.Equal(t, []string{"guitars/1", "guitars/2", "basses/1", "basses/2"}, modelsOf(store.Upserted))
+	})
+
+	t.Run("start from scratch if checkpoint is expired", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		startedAt := time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC)
+
+		cp, err := openCheckpoint(path, time.Hour, startedAt)
+		assert.NoError(t, err)
+		assert.NoError(t, cp.Complete(CheckpointUnit{Retailer: "Stub", Category: "guitars", Page: 1, LastPage: 2}))
+
+		cp, err = openCheckpoint(path, time.Hour, startedAt.Add(59*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, cp.Len())
+		assert.Equal(t, startedAt, cp.StartedAt())
+
+		cp, err = openCheckpoint(path, time.Hour, startedAt.Add(61*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, cp.Len())
+		assert.Equal(t, startedAt.Add(61*time.Minute), cp.StartedAt())
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("treat page zero as first page", func(t *testing.T) {
+		t.Parallel()
+
+		cp, err := OpenCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, cp.Complete(CheckpointUnit{Retailer: "Stub", Category: "guitars", Page: 0, LastPage: 1}))
+
+		unit, ok := cp.Unit("Stub", "guitars", 1)
+		assert.True(t, ok)
+		assert.Equal(t, uint(1), unit.Page)
+	})
+
+	t.Run("remove checkpoint after finished crawl", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		cp, err := OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+
+		var requested []string
+		assert.NoError(t, UpdateRetailersWithHealthCheck(&recordingStore{}, NewHealthMonitor(DefaultHealthThresholds), WithCheckpoint(newPagedRetailer(&requested, ""), cp)))
+		assert.FileExists(t, path)
+
+		assert.NoError(t, cp.Remove())
+		assert.NoFileExists(t, path)
+		assert.NoError(t, cp.Remove())
+	})
+
+	t.Run("append one line per completed page without its products", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		cp, err := OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+
+		var requested []string
+		assert.NoError(t, UpdateRetailersWithHealthCheck(&recordingStore{}, NewHealthMonitor(DefaultHealthThresholds), WithCheckpoint(newPagedRetailer(&requested, ""), cp)))
+
+		data, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Len(t, lines, 6)
+		assert.Contains(t, lines[0], "started_at")
+		assert.Contains(t, lines[4], `"category":"basses","page":2`)
+		assert.Contains(t, lines[5], `"crawled":"Stub"`)
+		assert.NotContains(t, string(data), "Fender")
+	})
+
+	t.Run("ignore a partially written last line", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		cp, err := OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, cp.Complete(CheckpointUnit{Retailer: "Stub", Category: "guitars", Page: 1, LastPage: 2}))
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		assert.NoError(t, err)
+		_, _ = f.WriteString(`{"unit": {"retailer": "Stub", "cat`)
+		assert.NoError(t, f.Close())
+
+		cp, err = OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, cp.Len())
+
+		assert.NoError(t, cp.Complete(CheckpointUnit{Retailer: "Stub", Category: "guitars", Page: 2, LastPage: 2}))
+		cp, err = OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, cp.Len())
+	})
+
+	t.Run("skip retailers whose products were already stored", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		cp, err := OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+
+		var requested []string
+		store := &recordingStore{}
+		err = UpdateRetailersWithHealthCheck(store, NewHealthMonitor(DefaultHealthThresholds), WithCheckpoint(newPagedRetailer(&requested, ""), cp))
+		assert.NoError(t, err)
+		assert.Len(t, store.Upserted, 4)
+
+		cp, err = OpenCheckpoint(path, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, cp.Crawled("Stub"))
+
+		requested = nil
+		store = &recordingStore{}
+		err = UpdateRetailersWithHealthCheck(store, NewHealthMonitor(DefaultHealthThresholds), WithCheckpoint(newPagedRetailer(&requested, ""), cp))
+		assert.NoError(t, err)
+		assert.Empty(t, requested)
+		assert.Empty(t, store.Upserted)
+	})
+
+	t.Run("return error on corrupt checkpoint", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(`{"units": [`), 0644))
+
+		_, err := OpenCheckpoint(path, time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+type recordingStore struct {
+	Upserted []Product
+}
+
+func (r *recordingStore) Upsert(prds []Product) error {
+	r.Upserted = append(r.Upserted, prds...)
+	return nil
+}
+
+func modelsOf(prds []Product) []string {
+	models := make([]string, len(prds))
+	for i, p := range prds {
+		models[i] = p.Model
+	}
+
+	return models
+}
@@ -0,0 +1,242 @@
+package retailer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Definition struct {
+	Name         string             `json:"name"`
+	URL          string             `json:"url"`
+	Categories   []string           `json:"categories"`
+	Currency     string             `json:"currency"`
+	Selectors    Selectors          `json:"selectors"`
+	Availability []AvailabilityRule `json:"availability"`
+}
+
+type Selectors struct {
+	Category      string `json:"category"`
+	Product       string `json:"product"`
+	Name          string `json:"name"`
+	Manufacturer  string `json:"manufacturer"`
+	Manufacturers string `json:"manufacturers"`
+	Price         string `json:"price"`
+	Availability  string `json:"availability"`
+	Link          string `json:"link"`
+	Image         string `json:"image"`
+	CurrentPage   string `json:"current_page"`
+	LastPage      string `json:"last_page"`
+}
+
+type AvailabilityRule struct {
+	Contains string `json:"contains"`
+	Score    int    `json:"score"`
+}
+
+func LoadDefinition(r io.Reader) (Definition, error) {
+	var d Definition
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return Definition{}, fmt.Errorf("could not decode retailer definition: %w", err)
+	}
+
+	return d, d.Validate()
+}
+
+func (d Definition) Validate() error {
+	if d.Name == "" {
+		return errors.New("retailer definition has no name")
+	}
+	if !strings.Contains(d.URL, "{category}") || !strings.Contains(d.URL, "{page}") {
+		return fmt.Errorf("url of retailer definition %s must contain {category} and {page}", d.Name)
+	}
+	if len(d.Categories) == 0 {
+		return fmt.Errorf("retailer definition %s has no categories", d.Name)
+	}
+
+	required := []struct{ name, selector string }{
+		{"product", d.Selectors.Product},
+		{"name", d.Selectors.Name},
+		{"price", d.Selectors.Price},
+		{"link", d.Selectors.Link},
+	}
+	for _, r := range required {
+		if r.selector == "" {
+			return fmt.Errorf("retailer definition %s has no %s selector", d.Name, r.name)
+		}
+	}
+
+	return nil
+}
+
+type ConfiguredRetailer struct {
+	http       httpGetter
+	definition Definition
+}
+
+func NewConfiguredRetailer(http httpGetter, definition Definition) (*ConfiguredRetailer, error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ConfiguredRetailer{http: http, definition: definition}, nil
+}
+
+func (c *ConfiguredRetailer) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	resp, err := c.http.Get(c.PageURL(category, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from %s: %w", c.definition.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from %s: status %d", c.definition.Name, resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+
+	currentPage, lastPage := c.parsePagination(doc, options)
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	categoryName := category
+	if c.definition.Selectors.Category != "" {
+		categoryName = strings.TrimSpace(doc.Find(c.definition.Selectors.Category).First().Text())
+	}
+
+	var manufacturers []string
+	if c.definition.Selectors.Manufacturers != "" {
+		manufacturers = doc.Find(c.definition.Selectors.Manufacturers).Map(func(i int, s *goquery.Selection) string {
+			return strings.TrimSpace(s.Text())
+		})
+	}
+
+	productNodes := doc.Find(c.definition.Selectors.Product)
+	prds := make([]Product, 0, len(productNodes.Nodes))
+	productNodes.Each(func(i int, s *goquery.Selection) {
+		p := c.parseProduct(s, manufacturers)
+		if p.Model == "" {
+			return
+		}
+
+		p.Category = categoryName
+		prds = append(prds, p)
+	})
+
+	return ProductResponse{Products: prds, CurrentPage: currentPage, LastPage: lastPage}, nil
+}
+
+func (c *ConfiguredRetailer) Categories() []string {
+	return c.definition.Categories
+}
+
+func (c *ConfiguredRetailer) Name() string {
+	return c.definition.Name
+}
+
+func (c *ConfiguredRetailer) PageURL(category string, options RequestOptions) string {
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	r := strings.NewReplacer("{category}", category, "{page}", strconv.Itoa(int(page)))
+	return r.Replace(c.definition.URL)
+}
+
+func (c *ConfiguredRetailer) parseProduct(s *goquery.Selection, manufacturers []string) Product {
+	sel := c.definition.Selectors
+
+	name := strings.TrimSpace(s.Find(sel.Name).First().Text())
+	manufacturer := ""
+	if sel.Manufacturer != "" {
+		manufacturer = strings.TrimSpace(s.Find(sel.Manufacturer).First().Text())
+	}
+	for _, m := range manufacturers {
+		if manufacturer == "" && strings.HasPrefix(name, m+" ") {
+			manufacturer = m
+		}
+	}
+
+	p := Product{
+		Retailer:          c.definition.Name,
+		Price:             parseLocalizedPrice(s.Find(sel.Price).First().Text()),
+		Currency:          c.definition.Currency,
+		AvailabilityScore: AvailabilityUnknown,
+		ProductURL:        c.resolveURL(s.Find(sel.Link).First().AttrOr("href", "")),
+	}
+	p.Manufacturer, p.Model = splitProductName(name, manufacturer)
+
+	if sel.Image != "" {
+		img := s.Find(sel.Image).First()
+		p.ThumbnailURL = c.resolveURL(img.AttrOr("data-src", img.AttrOr("src", "")))
+	}
+
+	if sel.Availability != "" {
+		av := s.Find(sel.Availability).First()
+		p.AvailabilityInfo = strings.TrimSpace(av.Text())
+		p.AvailabilityScore = matchAvailabilityRules(c.definition.Availability, av.AttrOr("class", "")+" "+p.AvailabilityInfo)
+	}
+	p.IsAvailable = p.AvailabilityScore != AvailabilityUnknown
+
+	return p
+}
+
+func matchAvailabilityRules(rules []AvailabilityRule, availability string) int {
+	for _, rule := range rules {
+		if strings.Contains(availability, rule.Contains) {
+			return rule.Score
+		}
+	}
+
+	return AvailabilityUnknown
+}
+
+func (c *ConfiguredRetailer) parsePagination(doc *goquery.Document, options RequestOptions) (currentPage, lastPage uint) {
+	currentPage = 1
+	if options.Page > 0 {
+		currentPage = options.Page
+	}
+	if sel := c.definition.Selectors.CurrentPage; sel != "" {
+		if cp, err := strconv.Atoi(strings.TrimSpace(doc.Find(sel).First().Text())); err == nil {
+			currentPage = uint(cp)
+		}
+	}
+
+	lastPage = currentPage
+	if sel := c.definition.Selectors.LastPage; sel != "" {
+		if lp, err := strconv.Atoi(strings.TrimSpace(doc.Find(sel).Last().Text())); err == nil && uint(lp) > lastPage {
+			lastPage = uint(lp)
+		}
+	}
+
+	return currentPage, lastPage
+}
+
+func (c *ConfiguredRetailer) resolveURL(ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	base, err := url.Parse(c.definition.URL)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return base.ResolveReference(u).String()
+}
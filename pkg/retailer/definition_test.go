@@ -0,0 +1,188 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLoadDefinition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("load a valid definition", func(t *testing.T) {
+		t.Parallel()
+
+		d := loadTestDefinition("musikproduktiv_definition.json")
+
+		assert.Equal(t, "Musik Produktiv", d.Name)
+		assert.Len(t, d.Categories, 4)
+		assert.Equal(t, "ul.artgrid li", d.Selectors.Product)
+		assert.Len(t, d.Availability, 3)
+	})
+
+	t.Run("return error for invalid definitions", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name       string
+			Definition string
+		}{
+			{Name: "malformed json", Definition: `{"name": `},
+			{Name: "missing name", Definition: `{"url": "https://example.com/{category}?p={page}", "categories": ["lh"]}`},
+			{Name: "url without placeholders", Definition: `{"name": "Example", "url": "https://example.com/", "categories": ["lh"]}`},
+			{Name: "missing categories", Definition: `{"name": "Example", "url": "https://example.com/{category}?p={page}"}`},
+			{Name: "missing selectors", Definition: `{"name": "Example", "url": "https://example.com/{category}?p={page}", "categories": ["lh"], "selectors": {"product": "li"}}`},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := LoadDefinition(strings.NewReader(tt.Definition))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestConfiguredRetailer_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse product from product page", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewConfiguredRetailer(newTestHTTPClientForFixture("musikproduktiv_guitars_eight_strings.html"), loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		response, err := c.LoadProducts("e-gitarre-linkshaender", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 1)
+		p := response.Products[0]
+		assert.Equal(t, "Musik Produktiv", p.Retailer)
+		assert.Equal(t, "Schecter", p.Manufacturer)
+		assert.Equal(t, "C-8 Deluxe LH SBK", p.Model)
+		assert.Equal(t, "E-Gitarre (Linkshänder), 8-saitig", p.Category)
+		assert.Equal(t, false, p.IsAvailable)
+		assert.Equal(t, AvailabilityUnknown, p.AvailabilityScore)
+		assert.Equal(t, float64(599), p.Price)
+		assert.Equal(t, "EUR", p.Currency)
+		assert.Equal(t, "https://www.musik-produktiv.de/schecter-c-8-deluxe-lh-sbk.html", p.ProductURL)
+		assert.Equal(t, "https://sc1.musik-produktiv.com/pic-010125643l/schecter-c-8-deluxe-lh-sbk.jpg", p.ThumbnailURL)
+	})
+
+	t.Run("map availability rules to availability scores", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewConfiguredRetailer(newTestHTTPClientForFixture("musikproduktiv_guitars_second_page.html"), loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		response, err := c.LoadProducts("e-gitarre-linkshaender", RequestOptions{Page: 2})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 20)
+		assert.Equal(t, AvailabilityAvailable, response.Products[0].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinDays, response.Products[11].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinWeeks, response.Products[12].AvailabilityScore)
+		assert.Equal(t, AvailabilityUnknown, response.Products[13].AvailabilityScore)
+	})
+
+	t.Run("split product names by the manufacturers listed on the page", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewConfiguredRetailer(newTestHTTPClientForFixture("musikproduktiv_guitars_second_page.html"), loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		response, err := c.LoadProducts("e-gitarre-linkshaender", RequestOptions{Page: 2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "ESP LTD", response.Products[0].Manufacturer)
+		assert.Equal(t, "Signature Iron Cross J.Hetfield Lefthand", response.Products[0].Model)
+	})
+
+	t.Run("parse pagination", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name                string
+			Fixture             string
+			ExpectedCurrentPage uint
+			ExpectedLastPage    uint
+		}{
+			{Name: "single page", Fixture: "musikproduktiv_guitars_eight_strings.html", ExpectedCurrentPage: 1, ExpectedLastPage: 1},
+			{Name: "multiple pages", Fixture: "musikproduktiv_guitars_second_page.html", ExpectedCurrentPage: 2, ExpectedLastPage: 6},
+			{Name: "last page", Fixture: "musikproduktiv_guitars_last_page.html", ExpectedCurrentPage: 6, ExpectedLastPage: 6},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				c, err := NewConfiguredRetailer(newTestHTTPClientForFixture(tt.Fixture), loadTestDefinition("musikproduktiv_definition.json"))
+				assert.NoError(t, err)
+
+				response, err := c.LoadProducts("e-gitarre-linkshaender", RequestOptions{})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedCurrentPage, response.CurrentPage)
+				assert.Equal(t, tt.ExpectedLastPage, response.LastPage)
+			})
+		}
+	})
+
+	t.Run("replace placeholders in url", func(t *testing.T) {
+		t.Parallel()
+
+		httpSpy := newTestHTTPClientForFixture("musikproduktiv_guitars_second_page.html")
+		c, err := NewConfiguredRetailer(httpSpy, loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		_, _ = c.LoadProducts("e-gitarre-linkshaender", RequestOptions{Page: 2})
+
+		assert.Equal(t, "https://www.musik-produktiv.de/e-gitarre-linkshaender/?p=2", httpSpy.lastURL)
+	})
+
+	t.Run("return error on unexpected status code", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusForbidden, Body: ioutil.NopCloser(strings.NewReader("Access denied"))}, nil
+		}}
+		c, err := NewConfiguredRetailer(httpStub, loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		_, err = c.LoadProducts("e-gitarre-linkshaender", RequestOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewConfiguredRetailer(newTestHTTPClientForFixture("musikproduktiv_guitars_second_page.html"), loadTestDefinition("musikproduktiv_definition.json"))
+		assert.NoError(t, err)
+
+		_, err = c.LoadProducts("e-gitarre-linkshaender", RequestOptions{Page: 1337})
+		assert.Error(t, err)
+	})
+}
+
+func loadTestDefinition(fixture string) Definition {
+	f, err := os.Open(path.Join("testdata", fixture))
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	d, err := LoadDefinition(f)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
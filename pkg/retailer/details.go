@@ -0,0 +1,95 @@
+package retailer
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SpecScaleLength  string = "scale_length"
+	SpecWeight              = "weight"
+	SpecNeckProfile         = "neck_profile"
+	SpecPickups             = "pickups"
+	SpecCaseIncluded        = "case_included"
+)
+
+var specNames = map[string]string{
+	"mensur":       SpecScaleLength,
+	"gewicht":      SpecWeight,
+	"halsprofil":   SpecNeckProfile,
+	"tonabnehmer":  SpecPickups,
+	"koffer":       SpecCaseIncluded,
+	"inkl. koffer": SpecCaseIncluded,
+	"inkl. gigbag": SpecCaseIncluded,
+}
+
+type DetailLoader interface {
+	LoadDetails(p Product) (Product, error)
+}
+
+type ProductFinder interface {
+	FindAll(Filter) ([]Product, error)
+}
+
+type DetailOptions struct {
+	Budget int
+	MaxAge time.Duration
+}
+
+func UpdateDetails(ps ProductUpserter, pf ProductFinder, r Retailer, options DetailOptions) error {
+	dl, ok := r.(DetailLoader)
+	if !ok {
+		return nil
+	}
+
+	prds, err := pf.FindAll(Filter{Retailer: r.Name(), ProductsPerPage: math.MaxUint32})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stale := staleProducts(prds, options.MaxAge, now)
+	if options.Budget > 0 && len(stale) > options.Budget {
+		stale = stale[:options.Budget]
+	}
+
+	updated := make([]Product, 0, len(stale))
+	for _, p := range stale {
+		d, err := dl.LoadDetails(p)
+		if err != nil {
+			_ = ps.Upsert(updated)
+			return err
+		}
+
+		d.DetailsUpdatedAt = now
+		updated = append(updated, d)
+	}
+
+	return ps.Upsert(updated)
+}
+
+func staleProducts(prds []Product, maxAge time.Duration, now time.Time) []Product {
+	stale := make([]Product, 0, len(prds))
+	for _, p := range prds {
+		if p.DetailsUpdatedAt.IsZero() || now.Sub(p.DetailsUpdatedAt) > maxAge {
+			stale = append(stale, p)
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].DetailsUpdatedAt.Before(stale[j].DetailsUpdatedAt)
+	})
+
+	return stale
+}
+
+func normalizeSpecName(name string) string {
+	name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), ":"))
+	if n, ok := specNames[strings.ToLower(name)]; ok {
+		return n
+	}
+
+	return name
+}
@@ -0,0 +1,100 @@
+package retailer
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUpdateDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("load details for products without details", func(t *testing.T) {
+		t.Parallel()
+
+		store := &testProductStore{Products: []Product{{Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS"}}}
+		r := stubDetailRetailer{}
+
+		err := UpdateDetails(store, store, r, DetailOptions{MaxAge: time.Hour})
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 1)
+		assert.Equal(t, "864 mm", store.Products[0].Specs[SpecScaleLength])
+		assert.False(t, store.Products[0].DetailsUpdatedAt.IsZero())
+	})
+
+	t.Run("skip products with recently updated details", func(t *testing.T) {
+		t.Parallel()
+
+		store := &testProductStore{Products: []Product{
+			{Model: "fresh", DetailsUpdatedAt: time.Now().Add(-time.Minute)},
+			{Model: "stale", DetailsUpdatedAt: time.Now().Add(-48 * time.Hour)},
+		}}
+		r := stubDetailRetailer{}
+
+		err := UpdateDetails(store, store, r, DetailOptions{MaxAge: 24 * time.Hour})
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 1)
+		assert.Equal(t, "stale", store.Products[0].Model)
+	})
+
+	t.Run("load no more details than the budget allows, oldest first", func(t *testing.T) {
+		t.Parallel()
+
+		store := &testProductStore{Products: []Product{
+			{Model: "old", DetailsUpdatedAt: time.Now().Add(-48 * time.Hour)},
+			{Model: "never"},
+			{Model: "older", DetailsUpdatedAt: time.Now().Add(-72 * time.Hour)},
+		}}
+		r := stubDetailRetailer{}
+
+		err := UpdateDetails(store, store, r, DetailOptions{Budget: 2, MaxAge: time.Hour})
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 2)
+		assert.Equal(t, "never", store.Products[0].Model)
+		assert.Equal(t, "older", store.Products[1].Model)
+	})
+
+	t.Run("keep loaded details when a detail page fails", func(t *testing.T) {
+		t.Parallel()
+
+		store := &testProductStore{Products: []Product{{Model: "first"}, {Model: "broken"}}}
+		r := stubDetailRetailer{err: map[string]error{"broken": errors.New("boom")}}
+
+		err := UpdateDetails(store, store, r, DetailOptions{})
+		assert.Error(t, err)
+
+		assert.Len(t, store.Products, 1)
+		assert.Equal(t, "first", store.Products[0].Model)
+	})
+
+	t.Run("do nothing if retailer has no detail pages", func(t *testing.T) {
+		t.Parallel()
+
+		store := &testProductStore{Products: []Product{{Model: "first"}}}
+		r := stubRetailer{}
+
+		err := UpdateDetails(store, store, r, DetailOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 1)
+		assert.Nil(t, store.Products[0].Specs)
+	})
+}
+
+type stubDetailRetailer struct {
+	stubRetailer
+	err map[string]error
+}
+
+func (s stubDetailRetailer) LoadDetails(p Product) (Product, error) {
+	if err := s.err[p.Model]; err != nil {
+		return Product{}, err
+	}
+
+	p.Specs = map[string]string{SpecScaleLength: "864 mm"}
+	return p, nil
+}
@@ -0,0 +1,194 @@
+package retailer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/url"
+	"strings"
+)
+
+type CategoryDiscoverer interface {
+	DiscoverCategories() ([]string, error)
+}
+
+type CategoryDiff struct {
+	Added   []string
+	Missing []string
+}
+
+func (d CategoryDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Missing) == 0
+}
+
+func DiffCategories(configured, discovered []string) CategoryDiff {
+	diff := CategoryDiff{Added: make([]string, 0), Missing: make([]string, 0)}
+
+	isConfigured := make(map[string]bool, len(configured))
+	for _, c := range configured {
+		isConfigured[c] = true
+	}
+	isDiscovered := make(map[string]bool, len(discovered))
+	for _, c := range discovered {
+		if !isConfigured[c] && !isDiscovered[c] {
+			diff.Added = append(diff.Added, c)
+		}
+		isDiscovered[c] = true
+	}
+	for _, c := range configured {
+		if !isDiscovered[c] {
+			diff.Missing = append(diff.Missing, c)
+		}
+	}
+
+	return diff
+}
+
+func DiscoverCategories(r Retailer) (CategoryDiff, error) {
+	cd, ok := r.(CategoryDiscoverer)
+	if !ok {
+		return CategoryDiff{}, nil
+	}
+
+	discovered, err := cd.DiscoverCategories()
+	if err != nil {
+		return CategoryDiff{}, fmt.Errorf("could not discover categories of %s: %w", r.Name(), err)
+	}
+
+	return DiffCategories(r.Categories(), discovered), nil
+}
+
+type extendedRetailer struct {
+	Retailer
+	categories []string
+}
+
+func WithAdditionalCategories(r Retailer, categories []string) Retailer {
+	if len(categories) == 0 {
+		return r
+	}
+
+	all := make([]string, 0, len(r.Categories())+len(categories))
+	all = append(all, r.Categories()...)
+	all = append(all, DiffCategories(r.Categories(), categories).Added...)
+
+	return extendedRetailer{Retailer: r, categories: all}
+}
+
+func (e extendedRetailer) Categories() []string {
+	return e.categories
+}
+
+func (e extendedRetailer) Unwrap() Retailer {
+	return e.Retailer
+}
+
+func discoverNavigationLinks(http httpGetter, pageURL string, selector string) ([]string, error) {
+	resp, err := http.Get(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]string, 0)
+	doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+		ref, err := url.Parse(s.AttrOr("href", ""))
+		if err != nil {
+			return
+		}
+		links = append(links, base.ResolveReference(ref).String())
+	})
+
+	return links, nil
+}
+
+func discoverSitemapLocations(http httpGetter, sitemapURL string, follow func(sitemap string) bool) ([]string, error) {
+	resp, err := http.Get(sitemapURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %w", sitemapURL, err)
+	}
+	defer resp.Body.Close()
+
+	locations := make([]string, 0)
+	sitemaps := make([]string, 0)
+
+	dec := xml.NewDecoder(resp.Body)
+	isIndex := false
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode sitemap %s: %w", sitemapURL, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "sitemapindex":
+			isIndex = true
+		case "loc":
+			var loc string
+			if err = dec.DecodeElement(&loc, &start); err != nil {
+				return nil, fmt.Errorf("could not decode sitemap %s: %w", sitemapURL, err)
+			}
+			if isIndex {
+				sitemaps = append(sitemaps, strings.TrimSpace(loc))
+			} else {
+				locations = append(locations, strings.TrimSpace(loc))
+			}
+		}
+	}
+
+	for _, sitemap := range sitemaps {
+		if !follow(sitemap) {
+			continue
+		}
+
+		l, err := discoverSitemapLocations(http, sitemap, follow)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, l...)
+	}
+
+	return locations, nil
+}
+
+func categorySlugs(links []string, prefix string, match func(slug string) bool) []string {
+	slugs := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, link := range links {
+		u, err := url.Parse(link)
+		if err != nil || u.RawQuery != "" || !strings.HasPrefix(u.Path, prefix) {
+			continue
+		}
+
+		slug := strings.TrimSuffix(strings.TrimPrefix(u.Path, prefix), "/")
+		if slug == "" || seen[slug] || !match(slug) {
+			continue
+		}
+
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+
+	return slugs
+}
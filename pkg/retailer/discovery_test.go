@@ -0,0 +1,104 @@
+package retailer
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffCategories(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Configured      []string
+		Discovered      []string
+		ExpectedAdded   []string
+		ExpectedMissing []string
+	}{
+		{
+			Name:            "no changes",
+			Configured:      []string{"guitars", "basses"},
+			Discovered:      []string{"basses", "guitars"},
+			ExpectedAdded:   []string{},
+			ExpectedMissing: []string{},
+		},
+		{
+			Name:            "new and removed categories",
+			Configured:      []string{"guitars", "basses", "banjos"},
+			Discovered:      []string{"guitars", "ukuleles", "basses", "ukuleles", "7-string-guitars"},
+			ExpectedAdded:   []string{"ukuleles", "7-string-guitars"},
+			ExpectedMissing: []string{"banjos"},
+		},
+		{
+			Name:            "nothing discovered",
+			Configured:      []string{"guitars"},
+			Discovered:      nil,
+			ExpectedAdded:   []string{},
+			ExpectedMissing: []string{"guitars"},
+		},
+	}
+
+	for _, tt := range tests {
+		diff := DiffCategories(tt.Configured, tt.Discovered)
+
+		assert.Equal(t, tt.ExpectedAdded, diff.Added, tt.Name)
+		assert.Equal(t, tt.ExpectedMissing, diff.Missing, tt.Name)
+	}
+}
+
+func TestDiscoverCategories(t *testing.T) {
+	t.Run("return empty diff if retailer can not discover categories", func(t *testing.T) {
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+
+		diff, err := DiscoverCategories(r)
+		assert.NoError(t, err)
+		assert.True(t, diff.IsEmpty())
+	})
+
+	t.Run("compare discovered with configured categories", func(t *testing.T) {
+		r := stubDiscoveringRetailer{categories: []string{"guitars", "ukuleles"}}
+		r.CategoriesFunc = func() []string { return []string{"guitars", "banjos"} }
+
+		diff, err := DiscoverCategories(r)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ukuleles"}, diff.Added)
+		assert.Equal(t, []string{"banjos"}, diff.Missing)
+	})
+
+	t.Run("return error if discovery fails", func(t *testing.T) {
+		r := stubDiscoveringRetailer{err: errors.New("sitemap not found")}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+
+		_, err := DiscoverCategories(r)
+		assert.Error(t, err)
+	})
+}
+
+func TestWithAdditionalCategories(t *testing.T) {
+	r := stubRetailer{}
+	r.CategoriesFunc = func() []string { return []string{"guitars", "basses"} }
+	r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+		p := Product{Manufacturer: "Kala", Model: "KA-15S LH", Category: category}
+		return ProductResponse{Products: []Product{p}, CurrentPage: 1, LastPage: 1}, nil
+	}
+
+	extended := WithAdditionalCategories(r, []string{"basses", "ukuleles"})
+
+	assert.Equal(t, []string{"guitars", "basses", "ukuleles"}, extended.Categories())
+	assert.Equal(t, r.Name(), extended.Name())
+
+	prds, err := LoadProducts(extended)
+	assert.NoError(t, err)
+	assert.Len(t, prds, 3)
+	assert.Equal(t, "ukuleles", prds[2].Category)
+}
+
+type stubDiscoveringRetailer struct {
+	stubRetailer
+	categories []string
+	err        error
+}
+
+func (s stubDiscoveringRetailer) DiscoverCategories() ([]string, error) {
+	return s.categories, s.err
+}
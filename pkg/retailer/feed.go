@@ -0,0 +1,317 @@
+package retailer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	FeedFormatXML string = "xml"
+	FeedFormatCSV        = "csv"
+)
+
+type FeedConfig struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Format   string `json:"format"`
+	Currency string `json:"currency"`
+}
+
+func LoadFeedConfigs(r io.Reader) ([]FeedConfig, error) {
+	configs := make([]FeedConfig, 0)
+
+	dec := json.NewDecoder(r)
+	for {
+		var c FeedConfig
+		err := dec.Decode(&c)
+		if errors.Is(err, io.EOF) {
+			return configs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode feed config: %w", err)
+		}
+
+		configs = append(configs, c)
+	}
+}
+
+type FeedRetailer struct {
+	http   httpGetter
+	config FeedConfig
+}
+
+func NewFeedRetailer(http httpGetter, config FeedConfig) (FeedRetailer, error) {
+	if config.Name == "" || config.Source == "" {
+		return FeedRetailer{}, errors.New("feed config needs a name and a source")
+	}
+
+	if config.Format == "" {
+		config.Format = FeedFormatXML
+		if ext := strings.ToLower(path.Ext(config.Source)); ext == ".csv" || ext == ".tsv" || ext == ".txt" {
+			config.Format = FeedFormatCSV
+		}
+	}
+	if config.Format != FeedFormatXML && config.Format != FeedFormatCSV {
+		return FeedRetailer{}, fmt.Errorf("unknown feed format %s", config.Format)
+	}
+
+	return FeedRetailer{http: http, config: config}, nil
+}
+
+func (f FeedRetailer) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	if options.Page > 1 {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is 1", options.Page)
+	}
+
+	r, err := f.open()
+	if err != nil {
+		return ProductResponse{}, err
+	}
+	defer r.Close()
+
+	prds := make([]Product, 0)
+	collect := func(item feedItem) {
+		if IsLeftHanded(item.Title) {
+			prds = append(prds, f.product(item))
+		}
+	}
+
+	if f.config.Format == FeedFormatCSV {
+		err = readCSVFeed(r, collect)
+	} else {
+		err = readXMLFeed(r, collect)
+	}
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not read feed of %s: %w", f.config.Name, err)
+	}
+
+	return ProductResponse{Products: prds, CurrentPage: 1, LastPage: 1}, nil
+}
+
+func (f FeedRetailer) Name() string {
+	return f.config.Name
+}
+
+func (f FeedRetailer) Categories() []string {
+	return []string{"feed"}
+}
+
+func (f FeedRetailer) PageURL(category string, options RequestOptions) string {
+	if !strings.HasPrefix(f.config.Source, "http://") && !strings.HasPrefix(f.config.Source, "https://") {
+		return ""
+	}
+
+	return f.config.Source
+}
+
+func (f FeedRetailer) open() (io.ReadCloser, error) {
+	if !strings.HasPrefix(f.config.Source, "http://") && !strings.HasPrefix(f.config.Source, "https://") {
+		file, err := os.Open(f.config.Source)
+		if err != nil {
+			return nil, fmt.Errorf("could not open feed of %s: %w", f.config.Name, err)
+		}
+
+		return file, nil
+	}
+
+	resp, err := f.http.Get(f.config.Source)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch feed of %s: %w", f.config.Name, err)
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not fetch feed of %s: status %d", f.config.Name, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func (f FeedRetailer) product(item feedItem) Product {
+	price, currency := parseFeedPrice(item.Price)
+	if sale, saleCurrency := parseFeedPrice(item.SalePrice); sale > 0 {
+		price, currency = sale, saleCurrency
+	}
+	if currency == "" {
+		currency = f.config.Currency
+	}
+
+	p := Product{
+		Retailer:     f.config.Name,
+		Category:     firstNonEmpty(item.ProductType, item.GoogleProductCategory),
+		Price:        price,
+		Currency:     currency,
+		GTIN:         item.GTIN,
+		Condition:    feedCondition(item.Condition),
+		ProductURL:   item.Link,
+		ThumbnailURL: item.ImageLink,
+	}
+	p.Manufacturer, p.Model = splitProductName(item.Title, item.Brand)
+	p.IsAvailable, p.AvailabilityScore = feedAvailability(item.Availability)
+	if i := strings.LastIndex(p.Category, ">"); i >= 0 {
+		p.Category = strings.TrimSpace(p.Category[i+1:])
+	}
+
+	return p
+}
+
+type feedItem struct {
+	ID                    string `xml:"id"`
+	Title                 string `xml:"title"`
+	Brand                 string `xml:"brand"`
+	Price                 string `xml:"price"`
+	SalePrice             string `xml:"sale_price"`
+	Availability          string `xml:"availability"`
+	Link                  string `xml:"link"`
+	ImageLink             string `xml:"image_link"`
+	GTIN                  string `xml:"gtin"`
+	Condition             string `xml:"condition"`
+	ProductType           string `xml:"product_type"`
+	GoogleProductCategory string `xml:"google_product_category"`
+}
+
+func readXMLFeed(r io.Reader, fn func(feedItem)) error {
+	dec := xml.NewDecoder(r)
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || (start.Name.Local != "item" && start.Name.Local != "entry") {
+			continue
+		}
+
+		var item feedItem
+		if err = dec.DecodeElement(&item, &start); err != nil {
+			return err
+		}
+
+		fn(item.trimmed())
+	}
+}
+
+func readCSVFeed(r io.Reader, fn func(feedItem)) error {
+	br := bufio.NewReader(r)
+	firstLine, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	cr := csv.NewReader(io.MultiReader(strings.NewReader(firstLine), br))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	if strings.Count(firstLine, "\t") > strings.Count(firstLine, ",") {
+		cr.Comma = '\t'
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		value := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		item := feedItem{
+			ID:                    value("id"),
+			Title:                 value("title"),
+			Brand:                 value("brand"),
+			Price:                 value("price"),
+			SalePrice:             value("sale_price"),
+			Availability:          value("availability"),
+			Link:                  value("link"),
+			ImageLink:             value("image_link"),
+			GTIN:                  value("gtin"),
+			Condition:             value("condition"),
+			ProductType:           value("product_type"),
+			GoogleProductCategory: value("google_product_category"),
+		}
+		fn(item.trimmed())
+	}
+}
+
+func (i feedItem) trimmed() feedItem {
+	return feedItem{
+		ID:                    strings.TrimSpace(i.ID),
+		Title:                 strings.TrimSpace(i.Title),
+		Brand:                 strings.TrimSpace(i.Brand),
+		Price:                 strings.TrimSpace(i.Price),
+		SalePrice:             strings.TrimSpace(i.SalePrice),
+		Availability:          strings.TrimSpace(i.Availability),
+		Link:                  strings.TrimSpace(i.Link),
+		ImageLink:             strings.TrimSpace(i.ImageLink),
+		GTIN:                  strings.TrimSpace(i.GTIN),
+		Condition:             strings.TrimSpace(i.Condition),
+		ProductType:           strings.TrimSpace(i.ProductType),
+		GoogleProductCategory: strings.TrimSpace(i.GoogleProductCategory),
+	}
+}
+
+func parseFeedPrice(price string) (float64, string) {
+	fields := strings.Fields(price)
+	if len(fields) == 0 {
+		return 0, ""
+	}
+
+	p := parseLocalizedPrice(fields[0])
+	if p == 0 {
+		return 0, ""
+	}
+	if len(fields) < 2 {
+		return p, ""
+	}
+
+	return p, strings.ToUpper(fields[1])
+}
+
+func feedAvailability(availability string) (isAvailable bool, score int) {
+	switch strings.ReplaceAll(strings.ToLower(availability), " ", "_") {
+	case "in_stock":
+		return true, AvailabilityAvailable
+	case "preorder", "backorder":
+		return true, AvailabilityWithinWeeks
+	default:
+		return false, AvailabilityUnknown
+	}
+}
+
+func feedCondition(condition string) string {
+	switch strings.ToLower(condition) {
+	case "":
+		return ""
+	case "new":
+		return ConditionNew
+	default:
+		return ConditionUsed
+	}
+}
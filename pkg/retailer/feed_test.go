@@ -0,0 +1,207 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestFeedRetailer_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("map left-handed products from xml feed", func(t *testing.T) {
+		t.Parallel()
+
+		feed, err := NewFeedRetailer(nil, FeedConfig{Name: "Saitenwerk", Source: path.Join("testdata", "merchant_feed.xml")})
+		assert.NoError(t, err)
+
+		response, err := feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(1), response.LastPage)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 3)
+		assert.Equal(t, "Saitenwerk", prds[0].Retailer)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "Player Stratocaster LH PF 3TS", prds[0].Model)
+		assert.Equal(t, "Linkshänder E-Gitarren", prds[0].Category)
+		assert.Equal(t, float64(799), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, "0885978102251", prds[0].GTIN)
+		assert.Equal(t, ConditionNew, prds[0].Condition)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, "https://www.saitenwerk.example/fender-player-stratocaster-lh-pf-3ts.html", prds[0].ProductURL)
+		assert.Equal(t, "https://www.saitenwerk.example/media/sw-10021.jpg", prds[0].ThumbnailURL)
+
+		assert.Equal(t, "Les Paul Standard 50s Left-Handed Heritage Cherry Sunburst", prds[1].Model)
+		assert.Equal(t, "Guitars", prds[1].Category)
+		assert.Equal(t, float64(2399), prds[1].Price)
+		assert.Equal(t, ConditionUsed, prds[1].Condition)
+		assert.Equal(t, AvailabilityWithinWeeks, prds[1].AvailabilityScore)
+
+		assert.Equal(t, "Ibanez", prds[2].Manufacturer)
+		assert.Equal(t, false, prds[2].IsAvailable)
+		assert.Equal(t, AvailabilityUnknown, prds[2].AvailabilityScore)
+		assert.Equal(t, "", prds[2].Condition)
+	})
+
+	t.Run("map left-handed products from csv feed", func(t *testing.T) {
+		t.Parallel()
+
+		feed, err := NewFeedRetailer(nil, FeedConfig{Name: "Guitar Garage", Source: path.Join("testdata", "merchant_feed.csv"), Currency: "GBP"})
+		assert.NoError(t, err)
+
+		response, err := feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, "Harley Benton", prds[0].Manufacturer)
+		assert.Equal(t, "TE-20HH LH Black", prds[0].Model)
+		assert.Equal(t, "Electric Guitars", prds[0].Category)
+		assert.Equal(t, float64(119), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, "4251147112345", prds[0].GTIN)
+
+		assert.Equal(t, "FG800L Natural, Left Hand", prds[1].Model)
+		assert.Equal(t, float64(229), prds[1].Price)
+		assert.Equal(t, "GBP", prds[1].Currency)
+		assert.Equal(t, ConditionUsed, prds[1].Condition)
+		assert.Equal(t, AvailabilityWithinWeeks, prds[1].AvailabilityScore)
+	})
+
+	t.Run("detect tab separated feeds", func(t *testing.T) {
+		t.Parallel()
+
+		feed, err := NewFeedRetailer(nil, FeedConfig{Name: "Guitar Garage", Source: path.Join("testdata", "merchant_feed.tsv")})
+		assert.NoError(t, err)
+
+		response, err := feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 1)
+		assert.Equal(t, "Squier", response.Products[0].Manufacturer)
+		assert.Equal(t, float64(459), response.Products[0].Price)
+		assert.Equal(t, "GBP", response.Products[0].Currency)
+	})
+
+	t.Run("load feed from url", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/feeds/google.xml" {
+				http.NotFound(w, r)
+				return
+			}
+			http.ServeFile(w, r, path.Join("testdata", "merchant_feed.xml"))
+		}))
+		t.Cleanup(server.Close)
+
+		feed, err := NewFeedRetailer(server.Client(), FeedConfig{Name: "Saitenwerk", Source: server.URL + "/feeds/google.xml"})
+		assert.NoError(t, err)
+
+		response, err := feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, response.Products, 3)
+
+		feed, err = NewFeedRetailer(server.Client(), FeedConfig{Name: "Saitenwerk", Source: server.URL + "/feeds/missing.xml"})
+		assert.NoError(t, err)
+
+		_, err = feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error if page does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		feed, err := NewFeedRetailer(nil, FeedConfig{Name: "Saitenwerk", Source: path.Join("testdata", "merchant_feed.xml")})
+		assert.NoError(t, err)
+
+		_, err = feed.LoadProducts(feed.Categories()[0], RequestOptions{Page: 2})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error on malformed feed", func(t *testing.T) {
+		t.Parallel()
+
+		source := path.Join(t.TempDir(), "broken.xml")
+		err := os.WriteFile(source, []byte(`<rss><channel><item><g:title>Fender Player Stratocaster LH</item>`), 0644)
+		assert.NoError(t, err)
+
+		feed, err := NewFeedRetailer(nil, FeedConfig{Name: "Broken", Source: source})
+		assert.NoError(t, err)
+
+		_, err = feed.LoadProducts(feed.Categories()[0], RequestOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseFeedPrice(t *testing.T) {
+	tests := []struct {
+		Price            string
+		ExpectedPrice    float64
+		ExpectedCurrency string
+	}{
+		{Price: "799.00 EUR", ExpectedPrice: 799, ExpectedCurrency: "EUR"},
+		{Price: "1,299.00 gbp", ExpectedPrice: 1299, ExpectedCurrency: "GBP"},
+		{Price: "1.299,50 EUR", ExpectedPrice: 1299.5, ExpectedCurrency: "EUR"},
+		{Price: "459", ExpectedPrice: 459, ExpectedCurrency: ""},
+		{Price: "n/a EUR", ExpectedPrice: 0, ExpectedCurrency: ""},
+	}
+
+	for _, tt := range tests {
+		price, currency := parseFeedPrice(tt.Price)
+		assert.Equal(t, tt.ExpectedPrice, price, tt.Price)
+		assert.Equal(t, tt.ExpectedCurrency, currency, tt.Price)
+	}
+}
+
+func TestNewFeedRetailer(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Config         FeedConfig
+		ExpectedFormat string
+		ExpectError    bool
+	}{
+		{Name: "xml by default", Config: FeedConfig{Name: "Shop", Source: "https://shop.example.com/feed"}, ExpectedFormat: FeedFormatXML},
+		{Name: "csv by extension", Config: FeedConfig{Name: "Shop", Source: "feeds/shop.CSV"}, ExpectedFormat: FeedFormatCSV},
+		{Name: "explicit format", Config: FeedConfig{Name: "Shop", Source: "feeds/shop.xml", Format: FeedFormatCSV}, ExpectedFormat: FeedFormatCSV},
+		{Name: "unknown format", Config: FeedConfig{Name: "Shop", Source: "feeds/shop.xml", Format: "json"}, ExpectError: true},
+		{Name: "missing name", Config: FeedConfig{Source: "feeds/shop.xml"}, ExpectError: true},
+		{Name: "missing source", Config: FeedConfig{Name: "Shop"}, ExpectError: true},
+	}
+
+	for _, tt := range tests {
+		feed, err := NewFeedRetailer(nil, tt.Config)
+		if tt.ExpectError {
+			assert.Error(t, err, tt.Name)
+			continue
+		}
+
+		assert.NoError(t, err, tt.Name)
+		assert.Equal(t, tt.ExpectedFormat, feed.config.Format, tt.Name)
+	}
+}
+
+func TestLoadFeedConfigs(t *testing.T) {
+	configs, err := LoadFeedConfigs(strings.NewReader(`{"name": "Saitenwerk", "source": "https://www.saitenwerk.example/feeds/google.xml"}
+{"name": "Guitar Garage", "source": "/var/feeds/guitargarage.csv", "currency": "GBP"}
+`))
+	assert.NoError(t, err)
+
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "Saitenwerk", configs[0].Name)
+	assert.Equal(t, "/var/feeds/guitargarage.csv", configs[1].Source)
+	assert.Equal(t, "GBP", configs[1].Currency)
+
+	_, err = LoadFeedConfigs(strings.NewReader(`{"name": `))
+	assert.Error(t, err)
+}
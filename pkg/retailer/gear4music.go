@@ -0,0 +1,171 @@
+package retailer
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+type Gear4musicStorefront struct {
+	Code         string
+	BaseURL      string
+	Currency     string
+	Categories   []string
+	Availability []AvailabilityRule
+}
+
+var (
+	Gear4musicUK = Gear4musicStorefront{
+		Code:     "uk",
+		BaseURL:  "https://www.gear4music.com",
+		Currency: "GBP",
+		Categories: []string{
+			"Guitar-and-Bass/Left-Handed-Electric-Guitars",
+			"Guitar-and-Bass/Left-Handed-Acoustic-Guitars",
+			"Guitar-and-Bass/Left-Handed-Classical-Guitars",
+			"Guitar-and-Bass/Left-Handed-Bass-Guitars",
+		},
+		Availability: []AvailabilityRule{
+			{Contains: "In stock", Score: AvailabilityAvailable},
+			{Contains: "days", Score: AvailabilityWithinDays},
+			{Contains: "weeks", Score: AvailabilityWithinWeeks},
+			{Contains: "Expected", Score: AvailabilityWithinWeeks},
+		},
+	}
+	Gear4musicDE = Gear4musicStorefront{
+		Code:     "de",
+		BaseURL:  "https://www.gear4music.de",
+		Currency: "EUR",
+		Categories: []string{
+			"de/Gitarre-und-Bass/Linkshander-E-Gitarren",
+			"de/Gitarre-und-Bass/Linkshander-Akustikgitarren",
+			"de/Gitarre-und-Bass/Linkshander-Klassische-Gitarren",
+			"de/Gitarre-und-Bass/Linkshander-E-Basse",
+		},
+		Availability: []AvailabilityRule{
+			{Contains: "Auf Lager", Score: AvailabilityAvailable},
+			{Contains: "Tagen", Score: AvailabilityWithinDays},
+			{Contains: "Wochen", Score: AvailabilityWithinWeeks},
+			{Contains: "Erwartet", Score: AvailabilityWithinWeeks},
+		},
+	}
+)
+
+type Gear4music struct {
+	http        httpGetter
+	storefronts []Gear4musicStorefront
+}
+
+func NewGear4music(http httpGetter, storefronts ...Gear4musicStorefront) Gear4music {
+	if len(storefronts) == 0 {
+		storefronts = []Gear4musicStorefront{Gear4musicUK, Gear4musicDE}
+	}
+
+	return Gear4music{http: http, storefronts: storefronts}
+}
+
+func (g Gear4music) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	storefront, path, err := g.storefront(category)
+	if err != nil {
+		return ProductResponse{}, err
+	}
+
+	resp, err := g.http.Get(g.buildURL(storefront, path, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from gear4music: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+	categoryName := strings.TrimSpace(doc.Find(".listing-header h1").Text())
+
+	currentPage, lastPage := parseNumberedPagination(doc.Find(".pagination").Children(), "current")
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	productNodes := doc.Find(".listing-grid .product-card")
+	prds := make([]Product, len(productNodes.Nodes))
+	productNodes.Each(func(i int, s *goquery.Selection) {
+		p := g.parseProduct(s, storefront)
+		p.Category = categoryName
+		prds[i] = p
+	})
+
+	return ProductResponse{
+		Products:    prds,
+		CurrentPage: currentPage,
+		LastPage:    lastPage,
+	}, nil
+}
+
+func (g Gear4music) Name() string {
+	return "Gear4music"
+}
+
+func (g Gear4music) Categories() []string {
+	categories := make([]string, 0)
+	for _, s := range g.storefronts {
+		for _, c := range s.Categories {
+			categories = append(categories, s.Code+":"+c)
+		}
+	}
+
+	return categories
+}
+
+func (g Gear4music) storefront(category string) (Gear4musicStorefront, string, error) {
+	parts := strings.SplitN(category, ":", 2)
+	if len(parts) == 2 {
+		for _, s := range g.storefronts {
+			if s.Code == parts[0] {
+				return s, parts[1], nil
+			}
+		}
+	}
+
+	return Gear4musicStorefront{}, "", fmt.Errorf("unknown gear4music storefront for category %s", category)
+}
+
+func (g Gear4music) PageURL(category string, options RequestOptions) string {
+	storefront, path, err := g.storefront(category)
+	if err != nil {
+		return ""
+	}
+
+	return g.buildURL(storefront, path, options)
+}
+
+func (g Gear4music) buildURL(storefront Gear4musicStorefront, path string, options RequestOptions) string {
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	return fmt.Sprintf("%s/%s?page=%d", storefront.BaseURL, path, page)
+}
+
+func (g Gear4music) parseProduct(s *goquery.Selection, storefront Gear4musicStorefront) Product {
+	stock := strings.TrimSpace(s.Find(".product-card-stock").Text())
+	score := matchAvailabilityRules(storefront.Availability, stock)
+
+	p := Product{
+		Retailer:          g.Name(),
+		Storefront:        storefront.Code,
+		IsAvailable:       score != AvailabilityUnknown,
+		AvailabilityInfo:  stock,
+		AvailabilityScore: score,
+		Price:             parseLocalizedPrice(s.Find(".product-card-price .c-val").Text()),
+		Currency:          storefront.Currency,
+		ProductURL:        storefront.BaseURL + s.Find("a.product-card-link").AttrOr("href", ""),
+		ThumbnailURL:      s.Find("img.product-card-image").AttrOr("src", ""),
+	}
+	title := strings.TrimSpace(s.Find(".product-card-title").Text())
+	p.Manufacturer, p.Model = splitProductName(title, s.AttrOr("data-brand", ""))
+
+	return p
+}
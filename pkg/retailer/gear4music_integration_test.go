@@ -0,0 +1,40 @@
+//go:build integration
+// +build integration
+
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGear4music_LoadProductsIntegration(t *testing.T) {
+	t.Parallel()
+
+	c := newIntegrationHTTPClient(t)
+
+	for _, storefront := range []Gear4musicStorefront{Gear4musicUK, Gear4musicDE} {
+		storefront := storefront
+		t.Run(storefront.Code, func(t *testing.T) {
+			t.Parallel()
+
+			g4m := NewGear4music(c, storefront)
+
+			response, err := g4m.LoadProducts(g4m.Categories()[0], RequestOptions{})
+			assert.NoError(t, err)
+
+			assert.NotEmpty(t, response.Products)
+
+			p := response.Products[0]
+			assert.Equal(t, "Gear4music", p.Retailer)
+			assert.Equal(t, storefront.Currency, p.Currency)
+			assert.NotZero(t, p.Manufacturer)
+			assert.NotZero(t, p.Model)
+			assert.NotZero(t, p.Category)
+			assert.NotZero(t, p.AvailabilityInfo)
+			assert.NotZero(t, p.Price)
+			assert.NotZero(t, p.ProductURL)
+			assert.NotZero(t, p.ThumbnailURL)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package retailer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGear4music_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse all products on a GBP product page", func(t *testing.T) {
+		t.Parallel()
+
+		g4m := NewGear4music(newTestHTTPClientForFixture("gear4music_uk_electric_guitars.html"))
+		response, err := g4m.LoadProducts("uk:Guitar-and-Bass/Left-Handed-Electric-Guitars", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 4)
+		assert.Equal(t, "Gear4music", prds[0].Retailer)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "Player Stratocaster Left Handed PF, Black", prds[0].Model)
+		assert.Equal(t, "Left Handed Electric Guitars", prds[0].Category)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, "In stock", prds[0].AvailabilityInfo)
+		assert.Equal(t, float64(649), prds[0].Price)
+		assert.Equal(t, "GBP", prds[0].Currency)
+		assert.Equal(t, "uk", prds[0].Storefront)
+		assert.Equal(t, "https://www.gear4music.com/Guitar-and-Bass/Fender-Player-Stratocaster-Left-Handed-PF-Black/3FB2", prds[0].ProductURL)
+		assert.Equal(t, "https://r2.gear4music.com/media/59/592047/250/preview.jpg", prds[0].ThumbnailURL)
+
+		assert.Equal(t, "Harley Benton", prds[1].Manufacturer)
+		assert.Equal(t, "ST-20LH Black", prds[1].Model)
+		assert.Equal(t, float64(2399), prds[2].Price)
+		assert.Equal(t, float64(99.99), prds[3].Price)
+	})
+
+	t.Run("parse all products on a EUR product page", func(t *testing.T) {
+		t.Parallel()
+
+		g4m := NewGear4music(newTestHTTPClientForFixture("gear4music_de_basses.html"))
+		response, err := g4m.LoadProducts("de:de/Gitarre-und-Bass/Linkshander-E-Basse", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, "Squier", prds[0].Manufacturer)
+		assert.Equal(t, "Affinity Jazz Bass Linkshänder LRL, Black", prds[0].Model)
+		assert.Equal(t, "Linkshänder E-Bässe", prds[0].Category)
+		assert.Equal(t, float64(319), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, "de", prds[0].Storefront)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, "https://www.gear4music.de/de/Gitarre-und-Bass/Squier-Affinity-Jazz-Bass-Linkshander-LRL-Black/2Y8Q", prds[0].ProductURL)
+		assert.Equal(t, float64(1029), prds[1].Price)
+		assert.Equal(t, AvailabilityWithinWeeks, prds[1].AvailabilityScore)
+	})
+
+	t.Run("map stock messages to availability scores", func(t *testing.T) {
+		t.Parallel()
+
+		g4m := NewGear4music(newTestHTTPClientForFixture("gear4music_uk_electric_guitars.html"))
+		response, err := g4m.LoadProducts("uk:Guitar-and-Bass/Left-Handed-Electric-Guitars", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, AvailabilityAvailable, response.Products[0].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinDays, response.Products[1].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinWeeks, response.Products[2].AvailabilityScore)
+		assert.Equal(t, AvailabilityUnknown, response.Products[3].AvailabilityScore)
+		assert.Equal(t, false, response.Products[3].IsAvailable)
+	})
+
+	t.Run("parse pagination", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name                string
+			Fixture             string
+			Category            string
+			ExpectedCurrentPage uint
+			ExpectedLastPage    uint
+		}{
+			{Name: "multiple pages", Fixture: "gear4music_uk_electric_guitars.html", Category: "uk:Guitar-and-Bass/Left-Handed-Electric-Guitars", ExpectedCurrentPage: 1, ExpectedLastPage: 2},
+			{Name: "single page", Fixture: "gear4music_de_basses.html", Category: "de:de/Gitarre-und-Bass/Linkshander-E-Basse", ExpectedCurrentPage: 1, ExpectedLastPage: 1},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				g4m := NewGear4music(newTestHTTPClientForFixture(tt.Fixture))
+				response, err := g4m.LoadProducts(tt.Category, RequestOptions{})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedCurrentPage, response.CurrentPage)
+				assert.Equal(t, tt.ExpectedLastPage, response.LastPage)
+			})
+		}
+	})
+
+	t.Run("build storefront urls depending on category and RequestOptions struct", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name        string
+			Category    string
+			Page        uint
+			ExpectedURL string
+		}{
+			{
+				Name:        "GBP storefront with zero-value RequestOptions",
+				Category:    "uk:Guitar-and-Bass/Left-Handed-Electric-Guitars",
+				ExpectedURL: "https://www.gear4music.com/Guitar-and-Bass/Left-Handed-Electric-Guitars?page=1",
+			},
+			{
+				Name:        "EUR storefront with valid RequestOptions",
+				Category:    "de:de/Gitarre-und-Bass/Linkshander-E-Basse",
+				Page:        2,
+				ExpectedURL: "https://www.gear4music.de/de/Gitarre-und-Bass/Linkshander-E-Basse?page=2",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				httpSpy := testHTTPClient{
+					getFunc: func(url string) (*http.Response, error) {
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					},
+				}
+				g4m := NewGear4music(&httpSpy)
+
+				_, _ = g4m.LoadProducts(tt.Category, RequestOptions{Page: tt.Page})
+
+				assert.Equal(t, tt.ExpectedURL, httpSpy.lastURL)
+			})
+		}
+	})
+
+	t.Run("return error for categories of unknown storefronts", func(t *testing.T) {
+		t.Parallel()
+
+		g4m := NewGear4music(newTestHTTPClientForFixture("gear4music_de_basses.html"), Gear4musicUK)
+
+		_, err := g4m.LoadProducts("de:de/Gitarre-und-Bass/Linkshander-E-Basse", RequestOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		g4m := NewGear4music(newTestHTTPClientForFixture("gear4music_uk_electric_guitars.html"))
+
+		_, err := g4m.LoadProducts("uk:Guitar-and-Bass/Left-Handed-Electric-Guitars", RequestOptions{Page: 1337})
+		assert.Error(t, err)
+	})
+}
+
+func TestGear4music_Categories(t *testing.T) {
+	g4m := NewGear4music(nil, Gear4musicUK, Gear4musicDE)
+
+	categories := g4m.Categories()
+
+	assert.Len(t, categories, len(Gear4musicUK.Categories)+len(Gear4musicDE.Categories))
+	assert.Equal(t, "uk:Guitar-and-Bass/Left-Handed-Electric-Guitars", categories[0])
+	assert.Equal(t, "de:de/Gitarre-und-Bass/Linkshander-E-Gitarren", categories[len(Gear4musicUK.Categories)])
+}
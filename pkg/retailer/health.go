@@ -0,0 +1,364 @@
+package retailer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	CrawlStatusOK               string = "ok"
+	CrawlStatusStructureChanged        = "structure_changed"
+	CrawlStatusFailed                  = "failed"
+	CrawlStatusSkipped                 = "skipped"
+)
+
+type CrawlStats struct {
+	Products      int `json:"products"`
+	MissingPrices int `json:"missing_prices"`
+	EmptyFields   int `json:"empty_fields"`
+}
+
+func NewCrawlStats(prds []Product) CrawlStats {
+	s := CrawlStats{Products: len(prds)}
+	for _, p := range prds {
+		if p.Price <= 0 {
+			s.MissingPrices++
+		}
+		if p.Manufacturer == "" || p.Model == "" || p.ProductURL == "" {
+			s.EmptyFields++
+		}
+	}
+
+	return s
+}
+
+func (s CrawlStats) add(o CrawlStats) CrawlStats {
+	return CrawlStats{
+		Products:      s.Products + o.Products,
+		MissingPrices: s.MissingPrices + o.MissingPrices,
+		EmptyFields:   s.EmptyFields + o.EmptyFields,
+	}
+}
+
+func (s CrawlStats) share(n int) float64 {
+	if s.Products == 0 {
+		return 0
+	}
+
+	return float64(n) / float64(s.Products)
+}
+
+type HealthThresholds struct {
+	MaxCountDrop         float64
+	MaxEmptyFieldShare   float64
+	MaxMissingPriceShare float64
+}
+
+var DefaultHealthThresholds = HealthThresholds{
+	MaxCountDrop:         0.5,
+	MaxEmptyFieldShare:   0.2,
+	MaxMissingPriceShare: 0.1,
+}
+
+func (t HealthThresholds) Check(previous, current CrawlStats) []string {
+	reasons := make([]string, 0)
+
+	if current.Products == 0 {
+		if previous.Products > 0 {
+			reasons = append(reasons, fmt.Sprintf("no products found, previously %d", previous.Products))
+		}
+		return reasons
+	}
+	if previous.Products > 0 {
+		drop := 1 - float64(current.Products)/float64(previous.Products)
+		if drop > t.MaxCountDrop {
+			reasons = append(reasons, fmt.Sprintf("product count dropped by %.0f%% from %d to %d", drop*100, previous.Products, current.Products))
+		}
+	}
+	if share := current.share(current.EmptyFields); share > t.MaxEmptyFieldShare {
+		reasons = append(reasons, fmt.Sprintf("%.0f%% of products have empty fields", share*100))
+	}
+	if share := current.share(current.MissingPrices); share > t.MaxMissingPriceShare {
+		reasons = append(reasons, fmt.Sprintf("%.0f%% of products have no price", share*100))
+	}
+
+	return reasons
+}
+
+type StructureAlert struct {
+	Retailer string     `json:"retailer"`
+	Reasons  []string   `json:"reasons"`
+	Previous CrawlStats `json:"previous"`
+	Current  CrawlStats `json:"current"`
+	RaisedAt time.Time  `json:"raised_at"`
+}
+
+type CrawlReport struct {
+	Retailer    string      `json:"retailer"`
+	Status      string      `json:"status"`
+	Stats       CrawlStats  `json:"stats"`
+	Error       string      `json:"error,omitempty"`
+	Cache       *CacheStats `json:"cache,omitempty"`
+	Breaker     string      `json:"breaker"`
+	HealthScore int         `json:"health_score"`
+	CrawledAt   time.Time   `json:"crawled_at"`
+}
+
+type HealthMonitor struct {
+	thresholds HealthThresholds
+	now        func() time.Time
+
+	mu              sync.Mutex
+	reports         map[string]CrawlReport
+	baselines       map[string]CrawlStats
+	held            map[string]heldCrawl
+	breakers        map[string]Breaker
+	breakerSettings BreakerSettings
+}
+
+type heldCrawl struct {
+	Alert    StructureAlert `json:"alert"`
+	Products []Product      `json:"products"`
+}
+
+func NewHealthMonitor(thresholds HealthThresholds) *HealthMonitor {
+	return &HealthMonitor{
+		thresholds:      thresholds,
+		now:             time.Now,
+		reports:         make(map[string]CrawlReport),
+		baselines:       make(map[string]CrawlStats),
+		held:            make(map[string]heldCrawl),
+		breakers:        make(map[string]Breaker),
+		breakerSettings: DefaultBreakerSettings,
+	}
+}
+
+func UpdateRetailersWithHealthCheck(ps ProductUpserter, hm *HealthMonitor, retailer ...Retailer) error {
+	for _, r := range retailer {
+		checkpoint, checkpointed := retailerCheckpoint(r)
+		if checkpointed && checkpoint.Crawled(r.Name()) {
+			continue
+		}
+		if !hm.allow(r.Name()) {
+			hm.skip(r.Name())
+			continue
+		}
+
+		var err error
+		if checkpointed {
+			err = updateRetailerFromCheckpoint(ps, hm, r, checkpoint)
+		} else {
+			err = updateRetailer(ps, hm, r)
+		}
+		if stats, ok := retailerCacheStats(r); ok {
+			hm.setCacheStats(r.Name(), stats)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func updateRetailer(ps ProductUpserter, hm *HealthMonitor, r Retailer) error {
+	prds, err := LoadProducts(r)
+	if err != nil {
+		hm.fail(r.Name(), err)
+		return nil
+	}
+
+	if !hm.check(r.Name(), NewCrawlStats(prds), prds) {
+		return nil
+	}
+
+	return ps.Upsert(prds)
+}
+
+// updateRetailerFromCheckpoint stores every page as soon as it is crawled and skips the pages of the
+// checkpoint. Once a page lacks prices or fields, it and all following pages are held back instead.
+func updateRetailerFromCheckpoint(ps ProductUpserter, hm *HealthMonitor, r Retailer, checkpoint *Checkpoint) error {
+	stats := checkpoint.Stats(r.Name())
+	held := make([]Product, 0)
+	holding := false
+
+	var storeErr error
+	for _, category := range r.Categories() {
+		err := eachPage(r, category, func(page uint, resp ProductResponse) error {
+			if _, ok := checkpoint.Unit(r.Name(), category, page); ok {
+				return nil
+			}
+
+			pageStats := NewCrawlStats(resp.Products)
+			stats = stats.add(pageStats)
+			if holding = holding || len(hm.thresholds.Check(CrawlStats{}, pageStats)) > 0; holding {
+				held = append(held, resp.Products...)
+				return nil
+			}
+
+			if storeErr = ps.Upsert(resp.Products); storeErr != nil {
+				return storeErr
+			}
+			unit := CheckpointUnit{Retailer: r.Name(), Category: category, Page: page, LastPage: resp.LastPage, Stats: pageStats}
+			storeErr = checkpoint.Complete(unit)
+			return storeErr
+		})
+		if storeErr != nil {
+			return storeErr
+		}
+		if err != nil {
+			hm.fail(r.Name(), err)
+			return nil
+		}
+	}
+
+	if !hm.check(r.Name(), stats, held) {
+		return nil
+	}
+	if len(held) > 0 {
+		if err := ps.Upsert(held); err != nil {
+			return err
+		}
+	}
+
+	return checkpoint.MarkCrawled(r.Name())
+}
+
+func (h *HealthMonitor) Reports() []CrawlReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	reports := make([]CrawlReport, 0, len(h.reports))
+	for _, r := range h.reports {
+		b := h.breaker(r.Retailer)
+		r.Breaker, r.HealthScore = b.State, b.HealthScore()
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Retailer < reports[j].Retailer
+	})
+
+	return reports
+}
+
+func (h *HealthMonitor) Alerts() []StructureAlert {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	alerts := make([]StructureAlert, 0, len(h.held))
+	for _, held := range h.held {
+		alerts = append(alerts, held.Alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Retailer < alerts[j].Retailer
+	})
+
+	return alerts
+}
+
+func (h *HealthMonitor) Confirm(ps ProductUpserter, retailer string) error {
+	h.mu.Lock()
+	held, ok := h.held[retailer]
+	delete(h.held, retailer)
+	h.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no structure alert for %s", retailer)
+	}
+
+	err := ps.Upsert(held.Products)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		if _, ok := h.held[retailer]; !ok {
+			h.held[retailer] = held
+		}
+		return err
+	}
+	h.baselines[retailer] = held.Alert.Current
+
+	report := h.reports[retailer]
+	report.Status = CrawlStatusOK
+	h.reports[retailer] = report
+
+	return nil
+}
+
+func (h *HealthMonitor) Dismiss(retailer string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.held[retailer]; !ok {
+		return fmt.Errorf("no structure alert for %s", retailer)
+	}
+	delete(h.held, retailer)
+
+	return nil
+}
+
+func (h *HealthMonitor) check(retailer string, current CrawlStats, prds []Product) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	last := h.baselines[retailer]
+	report := CrawlReport{Retailer: retailer, Status: CrawlStatusOK, Stats: current, CrawledAt: now}
+
+	reasons := h.thresholds.Check(last, current)
+	if len(reasons) == 0 {
+		delete(h.held, retailer)
+		h.reports[retailer] = report
+		h.baselines[retailer] = current
+		h.breakers[retailer] = h.breaker(retailer).record(h.breakerSettings, CrawlStatusOK, now)
+		return true
+	}
+
+	report.Status = CrawlStatusStructureChanged
+	h.reports[retailer] = report
+	h.breakers[retailer] = h.breaker(retailer).record(h.breakerSettings, CrawlStatusStructureChanged, now)
+	h.held[retailer] = heldCrawl{
+		Alert: StructureAlert{
+			Retailer: retailer,
+			Reasons:  reasons,
+			Previous: last,
+			Current:  current,
+			RaisedAt: now,
+		},
+		Products: prds,
+	}
+
+	return false
+}
+
+func (h *HealthMonitor) fail(retailer string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	h.reports[retailer] = CrawlReport{Retailer: retailer, Status: CrawlStatusFailed, Error: err.Error(), CrawledAt: now}
+	h.breakers[retailer] = h.breaker(retailer).record(h.breakerSettings, CrawlStatusFailed, now)
+}
+
+func (h *HealthMonitor) skip(retailer string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	report := h.reports[retailer]
+	report.Retailer = retailer
+	report.Status = CrawlStatusSkipped
+	report.Error = ""
+	h.reports[retailer] = report
+}
+
+func (h *HealthMonitor) setCacheStats(retailer string, stats CacheStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	report := h.reports[retailer]
+	report.Cache = &stats
+	h.reports[retailer] = report
+}
@@ -0,0 +1,190 @@
+package retailer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHealthThresholds_Check(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Previous        CrawlStats
+		Current         CrawlStats
+		ExpectedReasons []string
+	}{
+		{
+			Name:            "healthy crawl",
+			Previous:        CrawlStats{Products: 100},
+			Current:         CrawlStats{Products: 90, MissingPrices: 1, EmptyFields: 2},
+			ExpectedReasons: []string{},
+		},
+		{
+			Name:            "first crawl",
+			Previous:        CrawlStats{},
+			Current:         CrawlStats{Products: 12},
+			ExpectedReasons: []string{},
+		},
+		{
+			Name:            "product count drop",
+			Previous:        CrawlStats{Products: 100},
+			Current:         CrawlStats{Products: 40},
+			ExpectedReasons: []string{"product count dropped by 60% from 100 to 40"},
+		},
+		{
+			Name:            "no products anymore",
+			Previous:        CrawlStats{Products: 100},
+			Current:         CrawlStats{},
+			ExpectedReasons: []string{"no products found, previously 100"},
+		},
+		{
+			Name:            "empty fields and missing prices",
+			Previous:        CrawlStats{Products: 100},
+			Current:         CrawlStats{Products: 100, MissingPrices: 100, EmptyFields: 25},
+			ExpectedReasons: []string{"25% of products have empty fields", "100% of products have no price"},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.ExpectedReasons, DefaultHealthThresholds.Check(tt.Previous, tt.Current), tt.Name)
+	}
+}
+
+func TestUpdateRetailersWithHealthCheck(t *testing.T) {
+	crawledAt := time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC)
+
+	retailerWithProducts := func(n int) stubRetailer {
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			prds := make([]Product, n)
+			for i := range prds {
+				prds[i] = Product{Retailer: "Stub", Manufacturer: "Fender", Model: fmt.Sprintf("Player Stratocaster LH %d", i), Price: 799, ProductURL: "https://example.com"}
+			}
+			return ProductResponse{Products: prds, CurrentPage: 1, LastPage: 1}, nil
+		}
+		return r
+	}
+
+	t.Run("store products of healthy crawls", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		hm.now = func() time.Time { return crawledAt }
+
+		err := UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 10)
+		assert.Empty(t, hm.Alerts())
+		assert.Equal(t, []CrawlReport{{Retailer: "Stub", Status: CrawlStatusOK, Stats: CrawlStats{Products: 10}, Breaker: BreakerClosed, HealthScore: 100, CrawledAt: crawledAt}}, hm.Reports())
+	})
+
+	t.Run("hold products and raise alert if structure changed", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		hm.now = func() time.Time { return crawledAt }
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+
+		err := UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(2))
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 10)
+		assert.Equal(t, []StructureAlert{{
+			Retailer: "Stub",
+			Reasons:  []string{"product count dropped by 80% from 10 to 2"},
+			Previous: CrawlStats{Products: 10},
+			Current:  CrawlStats{Products: 2},
+			RaisedAt: crawledAt,
+		}}, hm.Alerts())
+		assert.Equal(t, CrawlStatusStructureChanged, hm.Reports()[0].Status)
+	})
+
+	t.Run("compare with the previous crawl instead of all stored products", func(t *testing.T) {
+		store := &testProductStore{Products: make([]Product, 100)}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+
+		assert.NoError(t, UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10)))
+		assert.NoError(t, UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(8)))
+
+		assert.Len(t, store.Products, 8)
+		assert.Empty(t, hm.Alerts())
+	})
+
+	t.Run("store held products once the alert is confirmed", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(2))
+
+		err := hm.Confirm(store, "Stub")
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 2)
+		assert.Empty(t, hm.Alerts())
+		assert.Equal(t, CrawlStatusOK, hm.Reports()[0].Status)
+
+		assert.Error(t, hm.Confirm(store, "Stub"))
+
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(2))
+		assert.Empty(t, hm.Alerts())
+	})
+
+	t.Run("keep the alert if the held products cannot be stored", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(2))
+
+		var alerts []StructureAlert
+		err := hm.Confirm(upserterFunc(func(prds []Product) error {
+			alerts = hm.Alerts()
+			return errors.New("disk full")
+		}), "Stub")
+
+		assert.Error(t, err)
+		assert.Empty(t, alerts)
+		assert.Len(t, hm.Alerts(), 1)
+		assert.Equal(t, CrawlStatusStructureChanged, hm.Reports()[0].Status)
+	})
+
+	t.Run("discard held products once the alert is dismissed", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(2))
+
+		err := hm.Dismiss("Stub")
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 10)
+		assert.Empty(t, hm.Alerts())
+		assert.Error(t, hm.Dismiss("Stub"))
+	})
+
+	t.Run("report failed crawls and keep existing products", func(t *testing.T) {
+		store := &testProductStore{}
+		hm := NewHealthMonitor(DefaultHealthThresholds)
+		_ = UpdateRetailersWithHealthCheck(store, hm, retailerWithProducts(10))
+
+		r := stubRetailer{}
+		r.CategoriesFunc = func() []string { return []string{"guitars"} }
+		r.LoadProductsFunc = func(category string, options RequestOptions) (ProductResponse, error) {
+			return ProductResponse{}, errors.New("unexpected response body structure")
+		}
+
+		err := UpdateRetailersWithHealthCheck(store, hm, r)
+		assert.NoError(t, err)
+
+		assert.Len(t, store.Products, 10)
+		assert.Equal(t, CrawlStatusFailed, hm.Reports()[0].Status)
+		assert.Equal(t, "unexpected response body structure", hm.Reports()[0].Error)
+	})
+}
+
+type upserterFunc func(prds []Product) error
+
+func (f upserterFunc) Upsert(prds []Product) error {
+	return f(prds)
+}
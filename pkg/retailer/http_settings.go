@@ -0,0 +1,171 @@
+package retailer
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+type HTTPConfig struct {
+	Default   HTTPSettings            `json:"default"`
+	Retailers map[string]HTTPSettings `json:"retailers"`
+}
+
+func LoadHTTPConfig(r io.Reader) (HTTPConfig, error) {
+	var c HTTPConfig
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return HTTPConfig{}, fmt.Errorf("could not decode http config: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c HTTPConfig) Settings(retailer string) HTTPSettings {
+	s, ok := c.Retailers[retailer]
+	if !ok {
+		return c.Default
+	}
+
+	return c.Default.merge(s)
+}
+
+type HTTPSettings struct {
+	ProxyURL           string            `json:"proxy_url"`
+	UserAgent          string            `json:"user_agent"`
+	Headers            map[string]string `json:"headers"`
+	Cookies            []HTTPCookie      `json:"cookies"`
+	Timeout            string            `json:"timeout"`
+	InsecureSkipVerify *bool             `json:"insecure_skip_verify"`
+	MinTLSVersion      string            `json:"min_tls_version"`
+}
+
+type HTTPCookie struct {
+	URL   string `json:"url"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func (s HTTPSettings) NewClient() (*HTTPClient, error) {
+	timeout := defaultTimeout
+	if s.Timeout != "" {
+		t, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %s: %w", s.Timeout, err)
+		}
+		timeout = t
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if s.ProxyURL != "" {
+		proxy, err := url.Parse(s.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %s: %w", s.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: s.InsecureSkipVerify != nil && *s.InsecureSkipVerify}
+	if s.MinTLSVersion != "" {
+		v, ok := tlsVersions[s.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown tls version %s", s.MinTLSVersion)
+		}
+		transport.TLSClientConfig.MinVersion = v
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range s.Cookies {
+		u, err := url.Parse(c.URL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid url %s for cookie %s", c.URL, c.Name)
+		}
+		jar.SetCookies(u, []*http.Cookie{{Name: c.Name, Value: c.Value, Path: "/"}})
+	}
+
+	headers := make(http.Header)
+	for k, v := range s.Headers {
+		headers.Set(k, v)
+	}
+	if s.UserAgent != "" {
+		headers.Set("User-Agent", s.UserAgent)
+	}
+
+	return &HTTPClient{
+		client:  &http.Client{Timeout: timeout, Transport: transport, Jar: jar},
+		headers: headers,
+	}, nil
+}
+
+func (s HTTPSettings) merge(o HTTPSettings) HTTPSettings {
+	merged := s
+	if o.ProxyURL != "" {
+		merged.ProxyURL = o.ProxyURL
+	}
+	if o.UserAgent != "" {
+		merged.UserAgent = o.UserAgent
+	}
+	if o.Timeout != "" {
+		merged.Timeout = o.Timeout
+	}
+	if o.MinTLSVersion != "" {
+		merged.MinTLSVersion = o.MinTLSVersion
+	}
+	if o.InsecureSkipVerify != nil {
+		merged.InsecureSkipVerify = o.InsecureSkipVerify
+	}
+
+	merged.Headers = make(map[string]string, len(s.Headers)+len(o.Headers))
+	for k, v := range s.Headers {
+		merged.Headers[k] = v
+	}
+	for k, v := range o.Headers {
+		merged.Headers[k] = v
+	}
+
+	merged.Cookies = make([]HTTPCookie, 0, len(s.Cookies)+len(o.Cookies))
+	merged.Cookies = append(merged.Cookies, s.Cookies...)
+	merged.Cookies = append(merged.Cookies, o.Cookies...)
+
+	return merged
+}
+
+type HTTPClient struct {
+	client  *http.Client
+	headers http.Header
+}
+
+func (c *HTTPClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	for k, v := range c.headers {
+		if req.Header.Get(k) == "" {
+			req.Header[k] = v
+		}
+	}
+
+	return c.client.Do(req)
+}
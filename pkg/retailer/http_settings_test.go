@@ -0,0 +1,157 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPSettings_NewClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("send configured headers, user agent and cookies", func(t *testing.T) {
+		t.Parallel()
+
+		var received *http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+		}))
+		t.Cleanup(server.Close)
+
+		settings := HTTPSettings{
+			UserAgent: "lefty/1.0",
+			Headers:   map[string]string{"Accept-Language": "de-DE"},
+			Cookies:   []HTTPCookie{{URL: server.URL, Name: "consent", Value: "all"}},
+		}
+		c, err := settings.NewClient()
+		assert.NoError(t, err)
+
+		_, err = c.Get(server.URL + "/de/linkshaender_modelle.html")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "lefty/1.0", received.Header.Get("User-Agent"))
+		assert.Equal(t, "de-DE", received.Header.Get("Accept-Language"))
+		cookie, err := received.Cookie("consent")
+		assert.NoError(t, err)
+		assert.Equal(t, "all", cookie.Value)
+	})
+
+	t.Run("keep headers of the request", func(t *testing.T) {
+		t.Parallel()
+
+		var received *http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+		}))
+		t.Cleanup(server.Close)
+
+		c, err := HTTPSettings{Headers: map[string]string{"Accept": "text/html"}}.NewClient()
+		assert.NoError(t, err)
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Accept", "application/json")
+		_, err = c.Do(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "application/json", received.Header.Get("Accept"))
+	})
+
+	t.Run("send requests through proxy", func(t *testing.T) {
+		t.Parallel()
+
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+		}))
+		t.Cleanup(proxy.Close)
+
+		c, err := HTTPSettings{ProxyURL: proxy.URL}.NewClient()
+		assert.NoError(t, err)
+
+		_, err = c.Get("http://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "http://www.musik-produktiv.de/e-gitarre-linkshaender/?p=1", proxied)
+	})
+
+	t.Run("skip tls verification if configured", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		c, err := HTTPSettings{}.NewClient()
+		assert.NoError(t, err)
+		_, err = c.Get(server.URL)
+		assert.Error(t, err)
+
+		insecure := true
+		c, err = HTTPSettings{InsecureSkipVerify: &insecure, MinTLSVersion: "1.2"}.NewClient()
+		assert.NoError(t, err)
+		_, err = c.Get(server.URL)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return error on invalid settings", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name     string
+			Settings HTTPSettings
+		}{
+			{Name: "timeout", Settings: HTTPSettings{Timeout: "five seconds"}},
+			{Name: "tls version", Settings: HTTPSettings{MinTLSVersion: "2.0"}},
+			{Name: "proxy", Settings: HTTPSettings{ProxyURL: "://proxy"}},
+			{Name: "cookie url", Settings: HTTPSettings{Cookies: []HTTPCookie{{URL: "thomann.de", Name: "consent"}}}},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.Settings.NewClient()
+			assert.Error(t, err, tt.Name)
+		}
+	})
+}
+
+func TestHTTPConfig_Settings(t *testing.T) {
+	config, err := LoadHTTPConfig(strings.NewReader(`{
+		"default": {"user_agent": "lefty/1.0", "timeout": "5s", "headers": {"Accept-Language": "de-DE"}},
+		"retailers": {
+			"Thomann": {
+				"timeout": "10s",
+				"headers": {"Accept": "text/html"},
+				"cookies": [{"url": "https://www.thomann.de", "name": "consent", "value": "all"}]
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	thomann := config.Settings("Thomann")
+	assert.Equal(t, "lefty/1.0", thomann.UserAgent)
+	assert.Equal(t, "10s", thomann.Timeout)
+	assert.Equal(t, map[string]string{"Accept-Language": "de-DE", "Accept": "text/html"}, thomann.Headers)
+	assert.Len(t, thomann.Cookies, 1)
+
+	mp := config.Settings("Musik Produktiv")
+	assert.Equal(t, "5s", mp.Timeout)
+	assert.Empty(t, mp.Cookies)
+
+	_, err = LoadHTTPConfig(strings.NewReader(`{"default": `))
+	assert.Error(t, err)
+
+	t.Run("let retailer settings turn off insecure tls", func(t *testing.T) {
+		config, err := LoadHTTPConfig(strings.NewReader(`{
+			"default": {"insecure_skip_verify": true},
+			"retailers": {"Thomann": {"insecure_skip_verify": false}}
+		}`))
+		assert.NoError(t, err)
+
+		assert.False(t, *config.Settings("Thomann").InsecureSkipVerify)
+		assert.True(t, *config.Settings("Musik Produktiv").InsecureSkipVerify)
+	})
+}
@@ -0,0 +1,41 @@
+//go:build integration
+// +build integration
+
+package retailer
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	integrationCassette     *Cassette
+	integrationCassetteErr  error
+	integrationCassetteOnce sync.Once
+)
+
+func newIntegrationHTTPClient(t *testing.T) httpGetter {
+	c := &http.Client{Timeout: 5 * time.Second}
+
+	dir := os.Getenv("LEFTY_CASSETTE")
+	if dir == "" {
+		return c
+	}
+
+	integrationCassetteOnce.Do(func() {
+		mode := os.Getenv("LEFTY_CASSETTE_MODE")
+		if mode == "" {
+			mode = CassetteReplay
+		}
+
+		integrationCassette, integrationCassetteErr = NewCassette(c, dir, mode)
+	})
+	if integrationCassetteErr != nil {
+		t.Fatal(integrationCassetteErr)
+	}
+
+	return integrationCassette
+}
@@ -0,0 +1,178 @@
+package retailer
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+type KytaryStorefront struct {
+	Code       string
+	BaseURL    string
+	Currency   string
+	Categories []string
+}
+
+var (
+	KytaryCZ = KytaryStorefront{
+		Code:     "cz",
+		BaseURL:  "https://www.kytary.cz",
+		Currency: "CZK",
+		Categories: []string{
+			"elektricke-kytary/levoruke",
+			"akusticke-kytary/levoruke",
+			"klasicke-kytary/levoruke",
+			"baskytary/levoruke",
+		},
+	}
+	KytaryPL = KytaryStorefront{
+		Code:     "pl",
+		BaseURL:  "https://www.kytary.pl",
+		Currency: "PLN",
+		Categories: []string{
+			"gitary-elektryczne/leworeczne",
+			"gitary-akustyczne/leworeczne",
+			"gitary-klasyczne/leworeczne",
+			"gitary-basowe/leworeczne",
+		},
+	}
+	KytaryDE = KytaryStorefront{
+		Code:     "de",
+		BaseURL:  "https://www.kytary.de",
+		Currency: "EUR",
+		Categories: []string{
+			"e-gitarren/linkshaender",
+			"akustikgitarren/linkshaender",
+			"konzertgitarren/linkshaender",
+			"e-baesse/linkshaender",
+		},
+	}
+)
+
+type Kytary struct {
+	http        httpGetter
+	storefronts []KytaryStorefront
+}
+
+func NewKytary(http httpGetter, storefronts ...KytaryStorefront) Kytary {
+	if len(storefronts) == 0 {
+		storefronts = []KytaryStorefront{KytaryCZ, KytaryPL, KytaryDE}
+	}
+
+	return Kytary{http: http, storefronts: storefronts}
+}
+
+func (k Kytary) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	storefront, path, err := k.storefront(category)
+	if err != nil {
+		return ProductResponse{}, err
+	}
+
+	resp, err := k.http.Get(k.buildURL(storefront, path, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from kytary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+	categoryName := strings.TrimSpace(doc.Find("h1.category-name").Text())
+
+	currentPage, lastPage := parseNumberedPagination(doc.Find(".pager").Children(), "active")
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	productNodes := doc.Find(".products .product")
+	prds := make([]Product, len(productNodes.Nodes))
+	productNodes.Each(func(i int, s *goquery.Selection) {
+		p := k.parseProduct(s, storefront)
+		p.Category = categoryName
+		prds[i] = p
+	})
+
+	return ProductResponse{
+		Products:    prds,
+		CurrentPage: currentPage,
+		LastPage:    lastPage,
+	}, nil
+}
+
+func (k Kytary) Name() string {
+	return "Kytary"
+}
+
+func (k Kytary) Categories() []string {
+	categories := make([]string, 0)
+	for _, s := range k.storefronts {
+		for _, c := range s.Categories {
+			categories = append(categories, s.Code+":"+c)
+		}
+	}
+
+	return categories
+}
+
+func (k Kytary) storefront(category string) (KytaryStorefront, string, error) {
+	parts := strings.SplitN(category, ":", 2)
+	if len(parts) == 2 {
+		for _, s := range k.storefronts {
+			if s.Code == parts[0] {
+				return s, parts[1], nil
+			}
+		}
+	}
+
+	return KytaryStorefront{}, "", fmt.Errorf("unknown kytary storefront for category %s", category)
+}
+
+func (k Kytary) PageURL(category string, options RequestOptions) string {
+	storefront, path, err := k.storefront(category)
+	if err != nil {
+		return ""
+	}
+
+	return k.buildURL(storefront, path, options)
+}
+
+func (k Kytary) buildURL(storefront KytaryStorefront, path string, options RequestOptions) string {
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	return fmt.Sprintf("%s/%s/?page=%d", storefront.BaseURL, path, page)
+}
+
+func (k Kytary) parseProduct(s *goquery.Selection, storefront KytaryStorefront) Product {
+	p, _ := ExtractProduct(s)
+	p.Retailer = k.Name()
+	p.Storefront = storefront.Code
+	if p.Currency == "" {
+		p.Currency = storefront.Currency
+	}
+
+	availability := s.Find(".availability")
+	p.AvailabilityInfo = strings.TrimSpace(availability.Text())
+	p.AvailabilityScore = k.parseAvailabilityScore(availability)
+	p.IsAvailable = !availability.HasClass("sold-out")
+
+	return p
+}
+
+func (k Kytary) parseAvailabilityScore(s *goquery.Selection) int {
+	if s.HasClass("in-stock") {
+		return AvailabilityAvailable
+	}
+	if s.HasClass("supplier-stock") {
+		return AvailabilityWithinDays
+	}
+	if s.HasClass("on-order") {
+		return AvailabilityWithinWeeks
+	}
+
+	return AvailabilityUnknown
+}
@@ -0,0 +1,152 @@
+package retailer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestKytary_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse all products on a product page", func(t *testing.T) {
+		t.Parallel()
+
+		ky := NewKytary(newTestHTTPClientForFixture("kytary_cz_electric_guitars.html"))
+		response, err := ky.LoadProducts("cz:elektricke-kytary/levoruke", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 3)
+		assert.Equal(t, "Kytary", prds[0].Retailer)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "Player Stratocaster LH PF 3TS", prds[0].Model)
+		assert.Equal(t, "Levoruké elektrické kytary", prds[0].Category)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, "Skladem > 5 ks", prds[0].AvailabilityInfo)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, float64(19990), prds[0].Price)
+		assert.Equal(t, "CZK", prds[0].Currency)
+		assert.Equal(t, "cz", prds[0].Storefront)
+		assert.Equal(t, "0885978954973", prds[0].GTIN)
+		assert.Equal(t, "https://www.kytary.cz/fender-player-stratocaster-lh-pf-3ts/", prds[0].ProductURL)
+		assert.Equal(t, "https://cdn.kytary.cz/pics/pt/m/fender-player-stratocaster-lh-pf-3ts.jpg", prds[0].ThumbnailURL)
+	})
+
+	t.Run("record the currency of each storefront", func(t *testing.T) {
+		t.Parallel()
+
+		ky := NewKytary(newTestHTTPClientForFixture("kytary_pl_basses.html"))
+		response, err := ky.LoadProducts("pl:gitary-basowe/leworeczne", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 2)
+		assert.Equal(t, "Sterling by Music Man", response.Products[1].Manufacturer)
+		assert.Equal(t, "StingRay Ray4 LH BK", response.Products[1].Model)
+		assert.Equal(t, float64(2299), response.Products[1].Price)
+		assert.Equal(t, "PLN", response.Products[1].Currency)
+		assert.Equal(t, "pl", response.Products[1].Storefront)
+	})
+
+	t.Run("calculate availability score for products", func(t *testing.T) {
+		t.Parallel()
+
+		ky := NewKytary(newTestHTTPClientForFixture("kytary_cz_electric_guitars.html"))
+		response, err := ky.LoadProducts("cz:elektricke-kytary/levoruke", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, AvailabilityAvailable, response.Products[0].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinDays, response.Products[1].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinWeeks, response.Products[2].AvailabilityScore)
+
+		ky = NewKytary(newTestHTTPClientForFixture("kytary_pl_basses.html"))
+		response, err = ky.LoadProducts("pl:gitary-basowe/leworeczne", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, AvailabilityUnknown, response.Products[1].AvailabilityScore)
+		assert.Equal(t, false, response.Products[1].IsAvailable)
+	})
+
+	t.Run("parse pagination", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name                string
+			Fixture             string
+			Category            string
+			ExpectedCurrentPage uint
+			ExpectedLastPage    uint
+		}{
+			{Name: "multiple pages", Fixture: "kytary_cz_electric_guitars.html", Category: "cz:elektricke-kytary/levoruke", ExpectedCurrentPage: 1, ExpectedLastPage: 2},
+			{Name: "single page", Fixture: "kytary_pl_basses.html", Category: "pl:gitary-basowe/leworeczne", ExpectedCurrentPage: 1, ExpectedLastPage: 1},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				ky := NewKytary(newTestHTTPClientForFixture(tt.Fixture))
+				response, err := ky.LoadProducts(tt.Category, RequestOptions{})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedCurrentPage, response.CurrentPage)
+				assert.Equal(t, tt.ExpectedLastPage, response.LastPage)
+			})
+		}
+	})
+
+	t.Run("build country domain urls depending on category and RequestOptions struct", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name        string
+			Category    string
+			Page        uint
+			ExpectedURL string
+		}{
+			{Name: "czech domain", Category: "cz:baskytary/levoruke", ExpectedURL: "https://www.kytary.cz/baskytary/levoruke/?page=1"},
+			{Name: "polish domain", Category: "pl:gitary-basowe/leworeczne", Page: 3, ExpectedURL: "https://www.kytary.pl/gitary-basowe/leworeczne/?page=3"},
+			{Name: "german domain", Category: "de:e-baesse/linkshaender", Page: 2, ExpectedURL: "https://www.kytary.de/e-baesse/linkshaender/?page=2"},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				httpSpy := testHTTPClient{
+					getFunc: func(url string) (*http.Response, error) {
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					},
+				}
+				ky := NewKytary(&httpSpy)
+
+				_, _ = ky.LoadProducts(tt.Category, RequestOptions{Page: tt.Page})
+
+				assert.Equal(t, tt.ExpectedURL, httpSpy.lastURL)
+			})
+		}
+	})
+
+	t.Run("return error for categories of unknown storefronts", func(t *testing.T) {
+		t.Parallel()
+
+		ky := NewKytary(newTestHTTPClientForFixture("kytary_pl_basses.html"), KytaryCZ)
+
+		_, err := ky.LoadProducts("pl:gitary-basowe/leworeczne", RequestOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		ky := NewKytary(newTestHTTPClientForFixture("kytary_cz_electric_guitars.html"))
+
+		_, err := ky.LoadProducts("cz:elektricke-kytary/levoruke", RequestOptions{Page: 1337})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,103 @@
+package retailer
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+type MusicStore struct {
+	http httpGetter
+}
+
+func NewMusicStore(http httpGetter) MusicStore {
+	return MusicStore{http: http}
+}
+
+func (m MusicStore) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	resp, err := m.http.Get(m.PageURL(category, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from musicstore.de: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+	categoryName := strings.TrimSpace(doc.Find(".category-header h1").Text())
+
+	currentPage, lastPage := parseNumberedPagination(doc.Find(".pagination li"), "active")
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	productNodes := doc.Find(".product-list .tile-product")
+	prds := make([]Product, len(productNodes.Nodes))
+	productNodes.Each(func(i int, s *goquery.Selection) {
+		p := m.parseProduct(s)
+		p.Category = categoryName
+		prds[i] = p
+	})
+
+	return ProductResponse{
+		Products:    prds,
+		CurrentPage: currentPage,
+		LastPage:    lastPage,
+	}, nil
+}
+
+func (m MusicStore) Name() string {
+	return "Music Store"
+}
+
+func (m MusicStore) Categories() []string {
+	return []string{
+		"E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH",
+		"Akustik-Gitarren/Linkshaender-Westerngitarren/cat-GIT-GITWGLH",
+		"Klassik-Gitarren/Linkshaender-Konzertgitarren/cat-GIT-GITKGLH",
+		"E-Baesse/Linkshaender-E-Baesse/cat-BAS-BASEBLH",
+	}
+}
+
+func (m MusicStore) PageURL(category string, options RequestOptions) string {
+	var productsPerPage uint = 48
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	return fmt.Sprintf("https://www.musicstore.de/de_DE/EUR/%s?ps=%d&pn=%d", category, productsPerPage, page)
+}
+
+func (m MusicStore) parseProduct(s *goquery.Selection) Product {
+	status := s.Find(".delivery-status")
+
+	return Product{
+		Retailer:          m.Name(),
+		Manufacturer:      strings.TrimSpace(s.Find(".tile-product-brand").Text()),
+		Model:             strings.TrimSpace(s.Find(".tile-product-name").Text()),
+		IsAvailable:       !status.HasClass("state-red"),
+		AvailabilityInfo:  strings.TrimSpace(status.Text()),
+		AvailabilityScore: m.parseAvailabilityScore(status),
+		Price:             parseLocalizedPrice(s.Find(".price").First().Text()),
+		Currency:          "EUR",
+		ProductURL:        "https://www.musicstore.de" + s.Find("a.tile-product-link").AttrOr("href", ""),
+		ThumbnailURL:      s.Find(".tile-product-image img").AttrOr("data-src", ""),
+	}
+}
+
+func (m MusicStore) parseAvailabilityScore(s *goquery.Selection) int {
+	if s.HasClass("state-green") {
+		return AvailabilityAvailable
+	}
+	if s.HasClass("state-yellow") {
+		return AvailabilityWithinDays
+	}
+	if s.HasClass("state-orange") {
+		return AvailabilityWithinWeeks
+	}
+
+	return AvailabilityUnknown
+}
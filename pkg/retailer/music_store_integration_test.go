@@ -0,0 +1,31 @@
+//go:build integration
+// +build integration
+
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMusicStore_LoadProductsIntegration(t *testing.T) {
+	t.Parallel()
+
+	c := newIntegrationHTTPClient(t)
+	ms := MusicStore{http: c}
+
+	response, err := ms.LoadProducts(ms.Categories()[0], RequestOptions{})
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, response.Products)
+
+	p := response.Products[0]
+	assert.Equal(t, "Music Store", p.Retailer)
+	assert.NotZero(t, p.Manufacturer)
+	assert.NotZero(t, p.Model)
+	assert.NotZero(t, p.Category)
+	assert.NotZero(t, p.AvailabilityInfo)
+	assert.NotZero(t, p.Price)
+	assert.NotZero(t, p.ProductURL)
+	assert.NotZero(t, p.ThumbnailURL)
+}
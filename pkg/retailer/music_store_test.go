@@ -0,0 +1,139 @@
+package retailer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMusicStore_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse all products on a product page", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_basses_single_page.html")}
+		response, err := ms.LoadProducts("E-Baesse/Linkshaender-E-Baesse/cat-BAS-BASEBLH", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, "Music Store", prds[0].Retailer)
+		assert.Equal(t, "Sterling by Music Man", prds[0].Manufacturer)
+		assert.Equal(t, "StingRay Ray4 LH Black", prds[0].Model)
+		assert.Equal(t, "Linkshänder E-Bässe", prds[0].Category)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, "Sofort lieferbar", prds[0].AvailabilityInfo)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, float64(499), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, "https://www.musicstore.de/de_DE/EUR/Sterling-by-Music-Man-StingRay-Ray4-LH-Black/art-BAS0011873-000", prds[0].ProductURL)
+		assert.Equal(t, "https://images.musicstore.de/images/0240/sterling-by-music-man-stingray-ray4-lh-black_1_BAS0011873-000.jpg", prds[0].ThumbnailURL)
+
+		assert.Equal(t, "Squier", prds[1].Manufacturer)
+		assert.Equal(t, "Affinity Precision Bass PJ LH Black", prds[1].Model)
+		assert.Equal(t, float64(289), prds[1].Price)
+	})
+
+	t.Run("parse prices with thousands separators", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_guitars_first_page.html")}
+		response, err := ms.LoadProducts("E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(1049), response.Products[1].Price)
+		assert.Equal(t, float64(2799), response.Products[3].Price)
+	})
+
+	t.Run("calculate availability score for products", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_guitars_first_page.html")}
+		response, err := ms.LoadProducts("E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, AvailabilityAvailable, response.Products[0].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinDays, response.Products[1].AvailabilityScore)
+		assert.Equal(t, AvailabilityWithinWeeks, response.Products[2].AvailabilityScore)
+		assert.Equal(t, AvailabilityUnknown, response.Products[3].AvailabilityScore)
+		assert.Equal(t, false, response.Products[3].IsAvailable)
+	})
+
+	t.Run("parse pagination when there is only a single page", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_basses_single_page.html")}
+		response, err := ms.LoadProducts("E-Baesse/Linkshaender-E-Baesse/cat-BAS-BASEBLH", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(1), response.LastPage)
+	})
+
+	t.Run("parse pagination when there are multiple pages", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_guitars_first_page.html")}
+		response, err := ms.LoadProducts("E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH", RequestOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(3), response.LastPage)
+	})
+
+	t.Run("use correct pagination query parameters depending on RequestOptions struct", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name              string
+			Page              uint
+			ExpectedURLSuffix string
+		}{
+			{
+				Name:              "zero-value RequestOptions",
+				Page:              0,
+				ExpectedURLSuffix: "?ps=48&pn=1",
+			},
+			{
+				Name:              "valid RequestOptions",
+				Page:              2,
+				ExpectedURLSuffix: "?ps=48&pn=2",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				httpSpy := testHTTPClient{
+					getFunc: func(url string) (*http.Response, error) {
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					},
+				}
+				ms := MusicStore{http: &httpSpy}
+
+				options := RequestOptions{Page: tt.Page}
+				_, _ = ms.LoadProducts("E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH", options)
+
+				assert.Equal(t, tt.ExpectedURLSuffix, httpSpy.lastURL[strings.LastIndex(httpSpy.lastURL, "?"):])
+			})
+		}
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		ms := MusicStore{newTestHTTPClientForFixture("musicstore_guitars_first_page.html")}
+
+		options := RequestOptions{Page: 1337}
+		_, err := ms.LoadProducts("E-Gitarren/Linkshaender-E-Gitarren/cat-GIT-GITEGLH", options)
+
+		assert.Error(t, err)
+	})
+}
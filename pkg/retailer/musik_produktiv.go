@@ -3,6 +3,7 @@ package retailer
 import (
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -18,7 +19,7 @@ func NewMusikProduktiv(http httpGetter) *MusikProduktiv {
 }
 
 func (m *MusikProduktiv) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
-	resp, err := m.http.Get(m.buildURL(category, options))
+	resp, err := m.http.Get(m.PageURL(category, options))
 	if err != nil {
 		return ProductResponse{}, fmt.Errorf("could not fetch products from musik-produktiv.de: %w", err)
 	}
@@ -64,6 +65,53 @@ func (m *MusikProduktiv) LoadProducts(category string, options RequestOptions) (
 	}, nil
 }
 
+func (m *MusikProduktiv) LoadDetails(p Product) (Product, error) {
+	resp, err := m.http.Get(p.ProductURL)
+	if err != nil {
+		return Product{}, fmt.Errorf("could not fetch product details from musik-produktiv.de: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Product{}, fmt.Errorf("could not fetch product details from musik-produktiv.de: status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return Product{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+
+	structured := ExtractProducts(doc)
+	if len(structured) > 0 && p.GTIN == "" {
+		p.GTIN = structured[0].GTIN
+	}
+
+	specs := make(map[string]string)
+	doc.Find("table.details tr").Each(func(i int, s *goquery.Selection) {
+		cells := s.Find("td")
+		if len(cells.Nodes) != 2 {
+			return
+		}
+
+		name := cells.First().Text()
+		value := strings.TrimSpace(cells.Last().Text())
+		if value == "" {
+			return
+		}
+
+		specs[normalizeSpecName(name)] = value
+	})
+	if len(specs) > 0 {
+		p.Specs = specs
+	}
+
+	return p, nil
+}
+
+func (m *MusikProduktiv) Name() string {
+	return "Musik Produktiv"
+}
+
 func (m *MusikProduktiv) Categories() []string {
 	return []string{
 		"e-gitarre-linkshaender",
@@ -73,7 +121,20 @@ func (m *MusikProduktiv) Categories() []string {
 	}
 }
 
-func (m *MusikProduktiv) buildURL(category string, options RequestOptions) string {
+func (m *MusikProduktiv) DiscoverCategories() ([]string, error) {
+	locations, err := discoverSitemapLocations(m.http, "https://www.musik-produktiv.de/sitemap.xml", func(sitemap string) bool {
+		return strings.Contains(sitemap, "categor")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categorySlugs(locations, "/", func(slug string) bool {
+		return strings.Contains(slug, "linkshaender") && !strings.Contains(slug, "/") && !strings.HasSuffix(slug, ".html")
+	}), nil
+}
+
+func (m *MusikProduktiv) PageURL(category string, options RequestOptions) string {
 	var page uint = 1
 
 	if options.Page > 0 {
@@ -95,6 +156,7 @@ func (m *MusikProduktiv) parseProduct(s *goquery.Selection) (Product, error) {
 		Manufacturer:      manufacturer,
 		Model:             model,
 		Price:             price,
+		Currency:          "EUR",
 		IsAvailable:       !s.Find(".ampel").HasClass("zzz"),
 		AvailabilityScore: m.parseAvailabilityScore(s),
 		ProductURL:        s.Find("a").First().AttrOr("href", ""),
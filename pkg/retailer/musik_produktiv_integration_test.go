@@ -5,15 +5,13 @@ package retailer
 
 import (
 	"github.com/stretchr/testify/assert"
-	"net/http"
 	"testing"
-	"time"
 )
 
 func TestMusikProduktiv_LoadProductsIntegration(t *testing.T) {
 	t.Parallel()
 
-	c := &http.Client{Timeout: 5 * time.Second}
+	c := newIntegrationHTTPClient(t)
 	mp := MusikProduktiv{http: c}
 
 	response, err := mp.LoadProducts(mp.Categories()[0], RequestOptions{})
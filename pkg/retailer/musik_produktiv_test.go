@@ -28,6 +28,7 @@ func TestMusikProduktiv_LoadProducts(t *testing.T) {
 		assert.Equal(t, false, response.Products[0].IsAvailable)
 		assert.Equal(t, "", response.Products[0].AvailabilityInfo)
 		assert.Equal(t, float64(599), response.Products[0].Price)
+		assert.Equal(t, "EUR", response.Products[0].Currency)
 		assert.Equal(t, "https://www.musik-produktiv.de/schecter-c-8-deluxe-lh-sbk.html", response.Products[0].ProductURL)
 		assert.Equal(t, "https://sc1.musik-produktiv.com/pic-010125643l/schecter-c-8-deluxe-lh-sbk.jpg", response.Products[0].ThumbnailURL)
 	})
@@ -145,3 +146,68 @@ func TestMusikProduktiv_LoadProducts(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestMusikProduktiv_LoadDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse specifications from article page", func(t *testing.T) {
+		t.Parallel()
+
+		mp := MusikProduktiv{http: newTestHTTPClientForFixture("musikproduktiv_product_details.html")}
+		p := Product{Manufacturer: "Schecter", Model: "C-8 Deluxe LH SBK"}
+
+		p, err := mp.LoadDetails(p)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "C-8 Deluxe LH SBK", p.Model)
+		assert.Equal(t, "0839212009876", p.GTIN)
+		assert.Len(t, p.Specs, 6)
+		assert.Equal(t, "711 mm", p.Specs[SpecScaleLength])
+		assert.Equal(t, "3,9 kg", p.Specs[SpecWeight])
+		assert.Equal(t, "Thin C", p.Specs[SpecNeckProfile])
+		assert.Equal(t, "Schecter Diamond Active", p.Specs[SpecPickups])
+		assert.Equal(t, "optional", p.Specs[SpecCaseIncluded])
+		assert.Equal(t, "Linde", p.Specs["Korpus"])
+	})
+
+	t.Run("return error if the article page could not be loaded", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := testHTTPClient{
+			getFunc: func(url string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString("not found"))}, nil
+			},
+		}
+		mp := MusikProduktiv{http: &httpStub}
+
+		_, err := mp.LoadDetails(Product{ProductURL: "https://www.musik-produktiv.de/gone.html"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMusikProduktiv_DiscoverCategories(t *testing.T) {
+	fixtures := map[string]string{
+		"https://www.musik-produktiv.de/sitemap.xml":            "musikproduktiv_sitemap_index.xml",
+		"https://www.musik-produktiv.de/sitemap_categories.xml": "musikproduktiv_sitemap_categories.xml",
+	}
+	requested := make([]string, 0)
+	httpSpy := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+		requested = append(requested, url)
+		return newTestHTTPClientForFixture(fixtures[url]).Get(url)
+	}}
+	mp := MusikProduktiv{http: httpSpy}
+
+	categories, err := mp.DiscoverCategories()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"e-gitarre-linkshaender",
+		"westerngitarre-linkshaender",
+		"linkshaender-konzertgitarren",
+		"ukulele-linkshaender",
+	}, categories)
+	assert.Equal(t, []string{
+		"https://www.musik-produktiv.de/sitemap.xml",
+		"https://www.musik-produktiv.de/sitemap_categories.xml",
+	}, requested)
+}
@@ -0,0 +1,68 @@
+package retailer
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	priceCharsPattern    = regexp.MustCompile(`[^0-9.,]`)
+	priceDecimalsPattern = regexp.MustCompile(`[.,](\d{1,2})$`)
+)
+
+func parseLocalizedPrice(price string) float64 {
+	p := priceCharsPattern.ReplaceAllString(price, "")
+	p = strings.Trim(p, ".,")
+
+	decimals := priceDecimalsPattern.FindStringSubmatch(p)
+	if decimals != nil {
+		p = p[:len(p)-len(decimals[0])]
+	}
+
+	p = strings.NewReplacer(".", "", ",", "").Replace(p)
+	if decimals != nil {
+		p += "." + decimals[1]
+	}
+
+	fPrice, err := strconv.ParseFloat(p, 64)
+	if err != nil {
+		return 0
+	}
+
+	return fPrice
+}
+
+func parseNumberedPagination(items *goquery.Selection, activeClass string) (currentPage, lastPage uint) {
+	currentPage, lastPage = 1, 1
+
+	items.Each(func(i int, s *goquery.Selection) {
+		page, err := strconv.Atoi(strings.TrimSpace(s.Text()))
+		if err != nil {
+			return
+		}
+
+		if s.HasClass(activeClass) {
+			currentPage = uint(page)
+		}
+		if uint(page) > lastPage {
+			lastPage = uint(page)
+		}
+	})
+
+	return currentPage, lastPage
+}
+
+var leftHandedPattern = regexp.MustCompile(`(?i:\b(lh|lefty|left[\s-]?hand(ed)?|lefthander|linkshänder|linkshaender|linkshand)\b)|[0-9A-Z]LH\b`)
+
+func IsLeftHanded(s string) bool {
+	return leftHandedPattern.MatchString(s)
+}
+
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
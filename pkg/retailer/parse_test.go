@@ -0,0 +1,59 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseLocalizedPrice(t *testing.T) {
+	tests := []struct {
+		Price    string
+		Expected float64
+	}{
+		{Price: "€ 599,-", Expected: 599},
+		{Price: "1.819,00 €", Expected: 1819},
+		{Price: "£1,299.99", Expected: 1299.99},
+		{Price: "12 490 Kč", Expected: 12490},
+		{Price: "n/a", Expected: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Expected, parseLocalizedPrice(tt.Price), tt.Price)
+	}
+}
+
+func TestIsLeftHanded(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Expected bool
+	}{
+		{Name: "Fender Player Stratocaster LH PF 3TS", Expected: true},
+		{Name: "Gretsch G2622LH Strml. DC CB Gunmetal", Expected: true},
+		{Name: "Fender American Vintage II 1961 Stratocaster Left-Hand", Expected: true},
+		{Name: "Squier Affinity Jazz Bass Left Handed", Expected: true},
+		{Name: "E-Gitarre (Linkshänder), 8-saitig", Expected: true},
+		{Name: "Gibson Les Paul Standard 60s, Bourbon Burst", Expected: false},
+		{Name: "Marshall JCM800 2203 Head", Expected: false},
+		{Name: "Harley Benton Walh Series", Expected: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Expected, IsLeftHanded(tt.Name), tt.Name)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		Text     string
+		Expected []string
+	}{
+		{Text: "Fender AM Pro II Jazzmaster LH MN MYS", Expected: []string{"fender", "am", "pro", "ii", "jazzmaster", "lh", "mn", "mys"}},
+		{Text: "SQ CV 60s Jazzmaster LH, LRL/OW", Expected: []string{"sq", "cv", "60s", "jazzmaster", "lh", "lrl", "ow"}},
+		{Text: "E-Gitarren für Linkshänder", Expected: []string{"e", "gitarren", "für", "linkshänder"}},
+		{Text: " - ", Expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Expected, Tokenize(tt.Text), tt.Text)
+	}
+}
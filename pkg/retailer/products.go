@@ -1,7 +1,12 @@
 package retailer
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"time"
 )
 
@@ -9,31 +14,123 @@ const (
 	OrderPriceDesc          string = "-price"
 	OrderByAvailabilityAsc         = "availability"
 	OrderByAvailabilityDesc        = "-availability"
+	OrderByRelevance               = "relevance"
 	AvailabilityAvailable   int    = 1
 	AvailabilityWithinDays         = 2
 	AvailabilityWithinWeeks        = 3
 	AvailabilityUnknown            = 4
+	ConditionNew            string = "new"
+	ConditionUsed                  = "used"
 )
 
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrPageOutOfRange  = errors.New("page out of range")
+)
+
+type ProductRepository interface {
+	ProductFinder
+	ProductUpserter
+	Query(Filter) (QueryResult, error)
+	Count(Filter) (int, error)
+	Get(id string) (Product, error)
+	History(id string) ([]PricePoint, error)
+	Delete(id string) error
+}
+
 type Product struct {
-	Retailer          string    `json:"retailer"`
-	Manufacturer      string    `json:"manufacturer"`
-	Model             string    `json:"model"`
-	Category          string    `json:"category"`
-	IsAvailable       bool      `json:"is_available"`
-	AvailabilityInfo  string    `json:"availability_info"`
-	AvailabilityScore int       `json:"availability_score"`
-	Price             float64   `json:"price"`
-	ProductURL        string    `json:"product_url"`
-	ThumbnailURL      string    `json:"thumbnail_url"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	Retailer          string            `json:"retailer"`
+	Storefront        string            `json:"storefront,omitempty"`
+	ListingID         string            `json:"listing_id,omitempty"`
+	Manufacturer      string            `json:"manufacturer"`
+	Model             string            `json:"model"`
+	Category          string            `json:"category"`
+	Condition         string            `json:"condition,omitempty"`
+	IsAvailable       bool              `json:"is_available"`
+	AvailabilityInfo  string            `json:"availability_info"`
+	AvailabilityScore int               `json:"availability_score"`
+	Price             float64           `json:"price"`
+	Currency          string            `json:"currency"`
+	GTIN              string            `json:"gtin,omitempty"`
+	ProductURL        string            `json:"product_url"`
+	ThumbnailURL      string            `json:"thumbnail_url"`
+	Specs             map[string]string `json:"specs,omitempty"`
+	DetailsUpdatedAt  time.Time         `json:"details_updated_at"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+func (p Product) ID() string {
+	id := fmt.Sprintf("%s-%s-%s", p.Retailer, p.Manufacturer, p.Model)
+	if p.Storefront != "" {
+		id = fmt.Sprintf("%s-%s-%s-%s", p.Retailer, p.Storefront, p.Manufacturer, p.Model)
+	}
+	if p.ListingID != "" {
+		id += "-" + p.ListingID
+	}
+
+	return id
 }
 
 func (p Product) String() string {
 	return fmt.Sprintf("%s %s", p.Manufacturer, p.Model)
 }
 
+func (p Product) MergeDetails(existing Product) Product {
+	if p.Specs == nil {
+		p.Specs = existing.Specs
+		p.DetailsUpdatedAt = existing.DetailsUpdatedAt
+	}
+	if p.GTIN == "" {
+		p.GTIN = existing.GTIN
+	}
+
+	return p
+}
+
+type PricePoint struct {
+	Price       float64   `json:"price"`
+	Currency    string    `json:"currency"`
+	IsAvailable bool      `json:"is_available"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+func (p Product) PricePoint() PricePoint {
+	return PricePoint{Price: p.Price, Currency: p.Currency, IsAvailable: p.IsAvailable, RecordedAt: p.UpdatedAt}
+}
+
+func (pp PricePoint) Differs(o PricePoint) bool {
+	return pp.Price != o.Price || pp.Currency != o.Currency || pp.IsAvailable != o.IsAvailable
+}
+
+type ProductDump struct {
+	Products map[string]Product      `json:"products"`
+	History  map[string][]PricePoint `json:"history"`
+}
+
+// LoadProductDump also reads dumps that are a plain map of products without history.
+func LoadProductDump(r io.Reader) (ProductDump, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return ProductDump{}, fmt.Errorf("could not read products: %w", err)
+	}
+
+	var d ProductDump
+	if err = json.Unmarshal(data, &d); err != nil {
+		return ProductDump{}, fmt.Errorf("could not decode products: %w", err)
+	}
+	if d.Products == nil {
+		if err = json.Unmarshal(data, &d.Products); err != nil {
+			return ProductDump{}, fmt.Errorf("could not decode products: %w", err)
+		}
+	}
+	if d.History == nil {
+		d.History = make(map[string][]PricePoint)
+	}
+
+	return d, nil
+}
+
 type Filter struct {
 	Search          string
 	OrderBy         string
@@ -43,5 +140,49 @@ type Filter struct {
 }
 
 func (f Filter) HasFilterCriteria() bool {
-	return f.Search != "" || f.Retailer != ""
+	return len(Tokenize(f.Search)) > 0 || f.Retailer != ""
+}
+
+func (f Filter) Window(total uint) (Window, error) {
+	perPage := f.ProductsPerPage
+	if perPage == 0 {
+		perPage = 50
+	}
+	page := f.Page
+	if page == 0 {
+		page = 1
+	}
+
+	lastPage := uint(math.Ceil(float64(total) / float64(perPage)))
+	if lastPage == 0 {
+		lastPage = 1
+	}
+	if page > lastPage {
+		return Window{}, fmt.Errorf("%w: page %d of %d", ErrPageOutOfRange, page, lastPage)
+	}
+
+	w := Window{Page: page, LastPage: lastPage, Offset: (page - 1) * perPage, Limit: perPage}
+	if w.Offset+w.Limit > total {
+		w.Limit = total - w.Offset
+	}
+
+	return w, nil
+}
+
+type Window struct {
+	Page     uint
+	LastPage uint
+	Offset   uint
+	Limit    uint
+}
+
+type QueryResult struct {
+	Products []Product
+	Total    uint
+	Page     uint
+	LastPage uint
+}
+
+func NewQueryResult(prds []Product, total uint, w Window) QueryResult {
+	return QueryResult{Products: prds, Total: total, Page: w.Page, LastPage: w.LastPage}
 }
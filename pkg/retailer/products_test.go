@@ -3,6 +3,7 @@ package retailer
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestFilter_HasFilterCriteria(t *testing.T) {
@@ -11,6 +12,11 @@ func TestFilter_HasFilterCriteria(t *testing.T) {
 		assert.Equal(t, false, f.HasFilterCriteria())
 	})
 
+	t.Run("return false if the search has no letters or digits", func(t *testing.T) {
+		f := Filter{Search: " - "}
+		assert.Equal(t, false, f.HasFilterCriteria())
+	})
+
 	t.Run("return true if either search or retailer criteria is specified", func(t *testing.T) {
 		f := Filter{Search: "foo"}
 		assert.Equal(t, true, f.HasFilterCriteria())
@@ -19,3 +25,73 @@ func TestFilter_HasFilterCriteria(t *testing.T) {
 		assert.Equal(t, true, f.HasFilterCriteria())
 	})
 }
+
+func TestFilter_Window(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Filter   Filter
+		Total    uint
+		Expected Window
+	}{
+		{Name: "first page with defaults", Filter: Filter{}, Total: 120, Expected: Window{Page: 1, LastPage: 3, Offset: 0, Limit: 50}},
+		{Name: "remaining products on the last page", Filter: Filter{Page: 3}, Total: 120, Expected: Window{Page: 3, LastPage: 3, Offset: 100, Limit: 20}},
+		{Name: "products per page is larger than product count", Filter: Filter{ProductsPerPage: 50}, Total: 2, Expected: Window{Page: 1, LastPage: 1, Offset: 0, Limit: 2}},
+		{Name: "no products", Filter: Filter{Page: 1}, Total: 0, Expected: Window{Page: 1, LastPage: 1, Offset: 0, Limit: 0}},
+	}
+
+	for _, tt := range tests {
+		w, err := tt.Filter.Window(tt.Total)
+
+		assert.NoError(t, err, tt.Name)
+		assert.Equal(t, tt.Expected, w, tt.Name)
+	}
+
+	t.Run("return error if page does not exist", func(t *testing.T) {
+		_, err := Filter{Page: 100, ProductsPerPage: 50}.Window(2)
+		assert.ErrorIs(t, err, ErrPageOutOfRange)
+
+		_, err = Filter{Page: 2}.Window(0)
+		assert.ErrorIs(t, err, ErrPageOutOfRange)
+	})
+}
+
+func TestProduct_ID(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Product  Product
+		Expected string
+	}{
+		{Name: "identify product by retailer, manufacturer and model", Product: Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "Jazzmaster LH"}, Expected: "Thomann-Fender-Jazzmaster LH"},
+		{Name: "include storefront", Product: Product{Retailer: "Gear4music", Storefront: "uk", Manufacturer: "Fender", Model: "Jazzmaster LH"}, Expected: "Gear4music-uk-Fender-Jazzmaster LH"},
+		{Name: "include listing id", Product: Product{Retailer: "Reverb", ListingID: "61254871", Manufacturer: "Fender", Model: "Jazzmaster LH"}, Expected: "Reverb-Fender-Jazzmaster LH-61254871"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.Expected, tt.Product.ID())
+		})
+	}
+}
+
+func TestProduct_MergeDetails(t *testing.T) {
+	t.Run("keep details of the existing product if the new one has none", func(t *testing.T) {
+		updatedAt := time.Date(2021, 11, 4, 12, 0, 0, 0, time.UTC)
+		existing := Product{Price: 599, GTIN: "4533940123456", Specs: map[string]string{SpecScaleLength: "864 mm"}, DetailsUpdatedAt: updatedAt}
+
+		p := Product{Price: 549}.MergeDetails(existing)
+
+		assert.Equal(t, float64(549), p.Price)
+		assert.Equal(t, "4533940123456", p.GTIN)
+		assert.Equal(t, "864 mm", p.Specs[SpecScaleLength])
+		assert.Equal(t, updatedAt, p.DetailsUpdatedAt)
+	})
+
+	t.Run("prefer details of the new product", func(t *testing.T) {
+		existing := Product{Specs: map[string]string{SpecScaleLength: "864 mm"}}
+
+		p := Product{Specs: map[string]string{SpecScaleLength: "889 mm"}}.MergeDetails(existing)
+
+		assert.Equal(t, "889 mm", p.Specs[SpecScaleLength])
+	})
+}
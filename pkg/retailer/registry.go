@@ -0,0 +1,178 @@
+package retailer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type RetailerInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Country     string `json:"country"`
+	Homepage    string `json:"homepage"`
+	Logo        string `json:"logo"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type builtinRetailer struct {
+	info   RetailerInfo
+	create func(http httpGetter) Retailer
+}
+
+var builtinRetailers = []builtinRetailer{
+	{
+		info:   RetailerInfo{Name: "Thomann", Country: "DE", Homepage: "https://www.thomann.de", Logo: "https://www.thomann.de/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewThomann(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Musik Produktiv", Country: "DE", Homepage: "https://www.musik-produktiv.de", Logo: "https://www.musik-produktiv.de/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewMusikProduktiv(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Music Store", Country: "DE", Homepage: "https://www.musicstore.de", Logo: "https://www.musicstore.de/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewMusicStore(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Gear4music", Country: "GB", Homepage: "https://www.gear4music.com", Logo: "https://www.gear4music.com/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewGear4music(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Bax-shop", Country: "NL", Homepage: "https://www.bax-shop.nl", Logo: "https://www.bax-shop.nl/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewBaxShop(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Kytary", Country: "CZ", Homepage: "https://www.kytary.cz", Logo: "https://www.kytary.cz/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewKytary(http) },
+	},
+	{
+		info:   RetailerInfo{Name: "Reverb", DisplayName: "Reverb (used)", Country: "US", Homepage: "https://reverb.com", Logo: "https://reverb.com/favicon.ico"},
+		create: func(http httpGetter) Retailer { return NewReverb(http, ReverbAPI) },
+	},
+}
+
+func BuiltinRetailers() []RetailerInfo {
+	infos := make([]RetailerInfo, len(builtinRetailers))
+	for i, b := range builtinRetailers {
+		infos[i] = b.info
+	}
+
+	return infos
+}
+
+func NewBuiltinRetailer(name string, http httpGetter) (Retailer, error) {
+	for _, b := range builtinRetailers {
+		if b.info.Name == name {
+			return b.create(http), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown retailer %s", name)
+}
+
+type RegistryConfig struct {
+	Retailers map[string]RetailerSettings `json:"retailers"`
+}
+
+type RetailerSettings struct {
+	Enabled     *bool  `json:"enabled"`
+	DisplayName string `json:"display_name"`
+	Country     string `json:"country"`
+	Homepage    string `json:"homepage"`
+	Logo        string `json:"logo"`
+}
+
+func LoadRegistryConfig(r io.Reader) (RegistryConfig, error) {
+	var c RegistryConfig
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return RegistryConfig{}, fmt.Errorf("could not decode retailer config: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c RegistryConfig) apply(info RetailerInfo) RetailerInfo {
+	info.Enabled = true
+
+	s, ok := c.Retailers[info.Name]
+	if !ok {
+		return info
+	}
+
+	if s.Enabled != nil {
+		info.Enabled = *s.Enabled
+	}
+	if s.DisplayName != "" {
+		info.DisplayName = s.DisplayName
+	}
+	if s.Country != "" {
+		info.Country = s.Country
+	}
+	if s.Homepage != "" {
+		info.Homepage = s.Homepage
+	}
+	if s.Logo != "" {
+		info.Logo = s.Logo
+	}
+
+	return info
+}
+
+type Registry struct {
+	config RegistryConfig
+
+	mu        sync.Mutex
+	infos     []RetailerInfo
+	retailers []Retailer
+}
+
+func NewRegistry(config RegistryConfig) *Registry {
+	return &Registry{config: config}
+}
+
+func (r *Registry) Register(info RetailerInfo, retailer Retailer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info.Name = retailer.Name()
+	for _, i := range r.infos {
+		if i.Name == info.Name {
+			return fmt.Errorf("retailer %s is already registered", info.Name)
+		}
+	}
+
+	info = r.config.apply(info)
+	if info.DisplayName == "" {
+		info.DisplayName = info.Name
+	}
+
+	r.infos = append(r.infos, info)
+	r.retailers = append(r.retailers, retailer)
+
+	return nil
+}
+
+func (r *Registry) Infos() []RetailerInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]RetailerInfo, len(r.infos))
+	copy(infos, r.infos)
+
+	return infos
+}
+
+func (r *Registry) Retailers() []Retailer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	retailers := make([]Retailer, 0, len(r.retailers))
+	for i, retailer := range r.retailers {
+		if r.infos[i].Enabled {
+			retailers = append(retailers, retailer)
+		}
+	}
+
+	return retailers
+}
@@ -0,0 +1,104 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLoadRegistryConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decode retailer settings", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := LoadRegistryConfig(strings.NewReader(`{"retailers": {"Reverb": {"enabled": false}, "Kytary": {"display_name": "Kytary.cz"}}}`))
+		assert.NoError(t, err)
+
+		assert.False(t, *config.Retailers["Reverb"].Enabled)
+		assert.Nil(t, config.Retailers["Kytary"].Enabled)
+		assert.Equal(t, "Kytary.cz", config.Retailers["Kytary"].DisplayName)
+	})
+
+	t.Run("return error for malformed config", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadRegistryConfig(strings.NewReader(`{"retailers": [`))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewBuiltinRetailer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("create every builtin retailer by name", func(t *testing.T) {
+		t.Parallel()
+
+		for _, info := range BuiltinRetailers() {
+			r, err := NewBuiltinRetailer(info.Name, &testHTTPClient{})
+			assert.NoError(t, err)
+			assert.Equal(t, info.Name, r.Name())
+		}
+	})
+
+	t.Run("return error for unknown retailer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewBuiltinRetailer("Guitar Center", &testHTTPClient{})
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	config := RegistryConfig{Retailers: map[string]RetailerSettings{
+		"Reverb":  {Enabled: &disabled},
+		"Thomann": {DisplayName: "Thomann Music", Logo: "/static/thomann.png"},
+	}}
+
+	registry := NewRegistry(config)
+	for _, info := range BuiltinRetailers() {
+		r, _ := NewBuiltinRetailer(info.Name, &testHTTPClient{})
+		assert.NoError(t, registry.Register(info, r))
+	}
+	assert.NoError(t, registry.Register(RetailerInfo{Country: "DE"}, stubRetailer{}))
+
+	t.Run("list all retailers with metadata", func(t *testing.T) {
+		t.Parallel()
+
+		infos := registry.Infos()
+
+		assert.Len(t, infos, 8)
+		assert.Equal(t, RetailerInfo{
+			Name:        "Thomann",
+			DisplayName: "Thomann Music",
+			Country:     "DE",
+			Homepage:    "https://www.thomann.de",
+			Logo:        "/static/thomann.png",
+			Enabled:     true,
+		}, infos[0])
+		assert.Equal(t, "Reverb (used)", infos[6].DisplayName)
+		assert.False(t, infos[6].Enabled)
+		assert.Equal(t, RetailerInfo{Name: "Stub", DisplayName: "Stub", Country: "DE", Enabled: true}, infos[7])
+	})
+
+	t.Run("return only enabled retailers", func(t *testing.T) {
+		t.Parallel()
+
+		names := make([]string, 0)
+		for _, r := range registry.Retailers() {
+			names = append(names, r.Name())
+		}
+
+		assert.Equal(t, []string{"Thomann", "Musik Produktiv", "Music Store", "Gear4music", "Bax-shop", "Kytary", "Stub"}, names)
+	})
+
+	t.Run("return error for duplicate retailer", func(t *testing.T) {
+		t.Parallel()
+
+		err := registry.Register(RetailerInfo{}, NewThomann(&testHTTPClient{}))
+		assert.Error(t, err)
+	})
+}
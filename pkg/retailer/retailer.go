@@ -7,6 +7,7 @@ import (
 type Retailer interface {
 	LoadProducts(category string, options RequestOptions) (ProductResponse, error)
 	Categories() []string
+	Name() string
 }
 
 type ProductUpserter interface {
@@ -44,26 +45,40 @@ func LoadProducts(r Retailer) ([]Product, error) {
 }
 
 func loadProductsFromCategory(r Retailer, category string) ([]Product, error) {
-	var page uint = 1
-	resp, err := r.LoadProducts(category, RequestOptions{Page: page})
+	prds := make([]Product, 0)
+	err := eachPage(r, category, func(page uint, resp ProductResponse) error {
+		prds = append(prds, resp.Products...)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	prds := make([]Product, len(resp.Products))
-	copy(prds, resp.Products)
+	return prds, nil
+}
+
+func eachPage(r Retailer, category string, fn func(page uint, resp ProductResponse) error) error {
+	var page uint = 1
+	resp, err := r.LoadProducts(category, RequestOptions{Page: page})
+	if err != nil {
+		return err
+	}
+	if err = fn(page, resp); err != nil {
+		return err
+	}
 
 	for page < resp.LastPage {
 		page++
 		resp, err = r.LoadProducts(category, RequestOptions{Page: page})
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err = fn(page, resp); err != nil {
+			return err
 		}
-
-		prds = append(prds, resp.Products...)
 	}
 
-	return prds, nil
+	return nil
 }
 
 type RequestOptions struct {
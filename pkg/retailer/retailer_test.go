@@ -155,6 +155,10 @@ type testProductStore struct {
 	Products []Product
 }
 
+func (t *testProductStore) FindAll(Filter) ([]Product, error) {
+	return t.Products, nil
+}
+
 func (t *testProductStore) Upsert(prds []Product) error {
 	t.Products = make([]Product, len(prds))
 	copy(t.Products, prds)
@@ -173,3 +177,7 @@ func (s stubRetailer) LoadProducts(category string, options RequestOptions) (Pro
 func (s stubRetailer) Categories() []string {
 	return s.CategoriesFunc()
 }
+
+func (s stubRetailer) Name() string {
+	return "Stub"
+}
@@ -0,0 +1,517 @@
+package retailertest
+
+import (
+	"github.com/chrismeh/lefty/pkg/retailer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type RepositoryFactory func(t *testing.T) retailer.ProductRepository
+
+func RunProductRepositoryTests(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("Count", func(t *testing.T) { testCount(t, newRepository) })
+	t.Run("FindAll", func(t *testing.T) { testFindAll(t, newRepository) })
+	t.Run("Query", func(t *testing.T) { testQuery(t, newRepository) })
+	t.Run("Upsert", func(t *testing.T) { testUpsert(t, newRepository) })
+	t.Run("Get", func(t *testing.T) { testGet(t, newRepository) })
+	t.Run("History", func(t *testing.T) { testHistory(t, newRepository) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepository) })
+}
+
+var (
+	jazzmasterMYS = retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH MN MYS", Price: 1819, AvailabilityScore: 1}
+	jazzmaster3TS = retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "AM Pro II Jazzmaster LH 3TSB", Price: 1799, AvailabilityScore: 4}
+	jazzmasterSQ  = retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "SQ CV 60s Jazzmaster LH LRL OW", Price: 394, AvailabilityScore: 2}
+	sgStandard    = retailer.Product{Retailer: "Musik Produktiv", Manufacturer: "Epiphone", Model: "SG Standard Alpine White LH", Price: 449, AvailabilityScore: 3}
+)
+
+func seed(t *testing.T, newRepository RepositoryFactory, prds ...retailer.Product) retailer.ProductRepository {
+	t.Helper()
+
+	repo := newRepository(t)
+	if err := repo.Upsert(prds); err != nil {
+		t.Fatalf("could not seed repository: %s", err)
+	}
+
+	return repo
+}
+
+func models(prds []retailer.Product) []string {
+	m := make([]string, len(prds))
+	for i, p := range prds {
+		m[i] = p.Model
+	}
+
+	return m
+}
+
+func testCount(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("return number of products", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		count, err := repo.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("return number of products that match the filter criteria", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		count, err := repo.Count(retailer.Filter{Search: "Fender"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("return number of products of the selected retailer", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		count, err := repo.Count(retailer.Filter{Retailer: "Thomann"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func testFindAll(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("return a slice of products", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		prds, err := repo.FindAll(retailer.Filter{})
+		assert.NoError(t, err)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", prds[0].Model)
+	})
+
+	t.Run("return empty slice if there are no products", func(t *testing.T) {
+		repo := seed(t, newRepository)
+
+		prds, err := repo.FindAll(retailer.Filter{})
+		assert.NoError(t, err)
+
+		assert.NotNil(t, prds)
+		assert.Len(t, prds, 0)
+	})
+
+	t.Run("return a paginated slice of products", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmasterSQ)
+
+		prds, err := repo.FindAll(retailer.Filter{Page: 2, ProductsPerPage: 1})
+		assert.NoError(t, err)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, float64(1819), prds[0].Price)
+	})
+
+	t.Run("apply default pagination settings when pagination data is invalid", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmasterSQ)
+
+		tests := []struct {
+			Name            string
+			Page            uint
+			ProductsPerPage uint
+		}{
+			{Name: "products per page is larger than product count", Page: 1, ProductsPerPage: 50},
+			{Name: "page is zero", Page: 0, ProductsPerPage: 50},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				prds, err := repo.FindAll(retailer.Filter{Page: tt.Page, ProductsPerPage: tt.ProductsPerPage})
+				assert.NoError(t, err)
+
+				assert.Len(t, prds, 2)
+			})
+		}
+	})
+
+	t.Run("return slice of remaining products on the last page", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmasterSQ, jazzmaster3TS)
+
+		prds, err := repo.FindAll(retailer.Filter{Page: 2, ProductsPerPage: 2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"AM Pro II Jazzmaster LH MN MYS"}, models(prds))
+	})
+
+	t.Run("return only products that match the search term", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		tests := []struct {
+			Name          string
+			Search        string
+			ExpectedModel string
+		}{
+			{Name: "find product by model", Search: "SG", ExpectedModel: "SG Standard Alpine White LH"},
+			{Name: "find product by manufacturer", Search: "Fender", ExpectedModel: "AM Pro II Jazzmaster LH MN MYS"},
+			{Name: "find product by model, case insensitive", Search: "sg", ExpectedModel: "SG Standard Alpine White LH"},
+			{Name: "find product by multiple terms in any order", Search: "jazzmaster fender", ExpectedModel: "AM Pro II Jazzmaster LH MN MYS"},
+			{Name: "find product by multiple model terms", Search: "alpine SG lh", ExpectedModel: "SG Standard Alpine White LH"},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				prds, err := repo.FindAll(retailer.Filter{Search: tt.Search})
+				assert.NoError(t, err)
+
+				assert.Equal(t, []string{tt.ExpectedModel}, models(prds))
+			})
+		}
+	})
+
+	t.Run("return only products that match all search terms", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		prds, err := repo.FindAll(retailer.Filter{Search: "jazzmaster gibson"})
+		assert.NoError(t, err)
+
+		assert.Empty(t, prds)
+	})
+
+	t.Run("match search terms by prefix, category and specs", func(t *testing.T) {
+		es335 := retailer.Product{
+			Retailer:     "Thomann",
+			Manufacturer: "Epiphone",
+			Model:        "ES-335 LH",
+			Category:     "Semi-Hollow",
+			Specs:        map[string]string{retailer.SpecScaleLength: "628 mm"},
+			Price:        549,
+		}
+		repo := seed(t, newRepository, jazzmasterMYS, es335)
+
+		tests := []struct {
+			Name          string
+			Search        string
+			ExpectedModel string
+		}{
+			{Name: "find product by prefix", Search: "jazz fend", ExpectedModel: jazzmasterMYS.Model},
+			{Name: "find product by category and manufacturer", Search: "epiphone hollow", ExpectedModel: es335.Model},
+			{Name: "find product by spec", Search: "628", ExpectedModel: es335.Model},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				prds, err := repo.FindAll(retailer.Filter{Search: tt.Search})
+				assert.NoError(t, err)
+
+				assert.Equal(t, []string{tt.ExpectedModel}, models(prds))
+			})
+		}
+	})
+
+	t.Run("sort by relevance", func(t *testing.T) {
+		jazzmaster := retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "Jazzmaster", Price: 1819}
+		offset := retailer.Product{
+			Retailer:     "Thomann",
+			Manufacturer: "Squier",
+			Model:        "Paranormal Offset Telecaster LH",
+			Specs:        map[string]string{retailer.SpecNeckProfile: "Jazzmaster C"},
+			Price:        299,
+		}
+		repo := seed(t, newRepository, jazzmaster, jazzmasterSQ, offset, sgStandard)
+
+		prds, err := repo.FindAll(retailer.Filter{Search: "jazzmaster"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{offset.Model, jazzmasterSQ.Model, jazzmaster.Model}, models(prds))
+
+		prds, err = repo.FindAll(retailer.Filter{Search: "jazzmaster", OrderBy: retailer.OrderByRelevance})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{jazzmaster.Model, jazzmasterSQ.Model, offset.Model}, models(prds))
+	})
+
+	t.Run("ignore search terms without letters or digits", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		for _, order := range []string{"", retailer.OrderByRelevance} {
+			prds, err := repo.FindAll(retailer.Filter{Search: " - ", OrderBy: order})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{sgStandard.Model, jazzmasterMYS.Model}, models(prds), order)
+		}
+		count, err := repo.Count(retailer.Filter{Search: " - "})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		count, err = repo.Count(retailer.Filter{Search: " - ", Retailer: "Thomann"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("return only products that match the retailer filter criteria", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		prds, err := repo.FindAll(retailer.Filter{Retailer: "Thomann"})
+		assert.NoError(t, err)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+	})
+
+	t.Run("sort products", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmasterSQ, sgStandard)
+
+		tests := []struct {
+			Name           string
+			Order          string
+			ExpectedModels []string
+		}{
+			{
+				Name:           "price ascending by default",
+				Order:          "",
+				ExpectedModels: []string{"SQ CV 60s Jazzmaster LH LRL OW", "SG Standard Alpine White LH", "AM Pro II Jazzmaster LH MN MYS"},
+			},
+			{
+				Name:           "price descending",
+				Order:          retailer.OrderPriceDesc,
+				ExpectedModels: []string{"AM Pro II Jazzmaster LH MN MYS", "SG Standard Alpine White LH", "SQ CV 60s Jazzmaster LH LRL OW"},
+			},
+			{
+				Name:           "availability ascending",
+				Order:          retailer.OrderByAvailabilityAsc,
+				ExpectedModels: []string{"AM Pro II Jazzmaster LH MN MYS", "SQ CV 60s Jazzmaster LH LRL OW", "SG Standard Alpine White LH"},
+			},
+			{
+				Name:           "availability descending",
+				Order:          retailer.OrderByAvailabilityDesc,
+				ExpectedModels: []string{"SG Standard Alpine White LH", "SQ CV 60s Jazzmaster LH LRL OW", "AM Pro II Jazzmaster LH MN MYS"},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				prds, err := repo.FindAll(retailer.Filter{OrderBy: tt.Order})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedModels, models(prds))
+			})
+		}
+	})
+
+	t.Run("break ties by id in the direction of the sort order", func(t *testing.T) {
+		sunburst := retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "Player Stratocaster LH 3TS", Price: 799, AvailabilityScore: 1}
+		white := retailer.Product{Retailer: "Thomann", Manufacturer: "Fender", Model: "Player Stratocaster LH PWT", Price: 799, AvailabilityScore: 1}
+		repo := seed(t, newRepository, white, sunburst)
+
+		tests := []struct {
+			Order          string
+			ExpectedModels []string
+		}{
+			{Order: "", ExpectedModels: []string{sunburst.Model, white.Model}},
+			{Order: retailer.OrderPriceDesc, ExpectedModels: []string{white.Model, sunburst.Model}},
+			{Order: retailer.OrderByAvailabilityAsc, ExpectedModels: []string{sunburst.Model, white.Model}},
+			{Order: retailer.OrderByAvailabilityDesc, ExpectedModels: []string{white.Model, sunburst.Model}},
+		}
+
+		for _, tt := range tests {
+			prds, err := repo.FindAll(retailer.Filter{OrderBy: tt.Order})
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.ExpectedModels, models(prds), tt.Order)
+		}
+	})
+}
+
+func testQuery(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("return products, total and pages of one query", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmaster3TS, jazzmasterSQ, sgStandard)
+
+		result, err := repo.Query(retailer.Filter{Retailer: "Thomann", Page: 2, ProductsPerPage: 2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"AM Pro II Jazzmaster LH MN MYS"}, models(result.Products))
+		assert.Equal(t, uint(3), result.Total)
+		assert.Equal(t, uint(2), result.Page)
+		assert.Equal(t, uint(2), result.LastPage)
+	})
+
+	t.Run("return effective page if page is zero", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		result, err := repo.Query(retailer.Filter{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(1), result.Page)
+		assert.Equal(t, uint(1), result.LastPage)
+	})
+
+	t.Run("return first page if there are no matching products", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		result, err := repo.Query(retailer.Filter{Search: "Telecaster", Page: 1})
+		assert.NoError(t, err)
+
+		assert.Len(t, result.Products, 0)
+		assert.Equal(t, uint(0), result.Total)
+		assert.Equal(t, uint(1), result.LastPage)
+	})
+
+	t.Run("return error if page does not exist", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, jazzmasterSQ)
+
+		_, err := repo.Query(retailer.Filter{Page: 100, ProductsPerPage: 50})
+		assert.ErrorIs(t, err, retailer.ErrPageOutOfRange)
+
+		_, err = repo.FindAll(retailer.Filter{Page: 3, ProductsPerPage: 1})
+		assert.ErrorIs(t, err, retailer.ErrPageOutOfRange)
+	})
+}
+
+func testUpsert(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("save a new product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		p, err := repo.Get(jazzmasterMYS.ID())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "Fender", p.Manufacturer)
+		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", p.Model)
+		assert.Equal(t, float64(1819), p.Price)
+	})
+
+	t.Run("set timestamps when saving a new product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		p, err := repo.Get(jazzmasterMYS.ID())
+		assert.NoError(t, err)
+
+		assert.False(t, p.CreatedAt.IsZero())
+		assert.True(t, p.CreatedAt.Equal(p.UpdatedAt))
+	})
+
+	t.Run("keep created timestamp when saving an existing product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+		created, _ := repo.Get(jazzmasterMYS.ID())
+
+		assert.NoError(t, repo.Upsert([]retailer.Product{jazzmasterMYS}))
+
+		p, err := repo.Get(jazzmasterMYS.ID())
+		assert.NoError(t, err)
+
+		assert.True(t, created.CreatedAt.Equal(p.CreatedAt))
+		assert.False(t, p.UpdatedAt.Before(created.UpdatedAt))
+	})
+
+	t.Run("keep product details when saving an existing product without details", func(t *testing.T) {
+		detailed := jazzmasterMYS
+		detailed.Specs = map[string]string{retailer.SpecScaleLength: "648 mm"}
+		detailed.GTIN = "0885978742578"
+		repo := seed(t, newRepository, detailed)
+
+		updated := jazzmasterMYS
+		updated.Price = 1799
+		assert.NoError(t, repo.Upsert([]retailer.Product{updated}))
+
+		p, err := repo.Get(jazzmasterMYS.ID())
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(1799), p.Price)
+		assert.Equal(t, "648 mm", p.Specs[retailer.SpecScaleLength])
+		assert.Equal(t, "0885978742578", p.GTIN)
+	})
+
+	t.Run("keep products of different storefronts apart", func(t *testing.T) {
+		uk := retailer.Product{Retailer: "Gear4music", Storefront: "uk", Manufacturer: "Fender", Model: "Player Stratocaster LH", Price: 699, Currency: "GBP"}
+		de := retailer.Product{Retailer: "Gear4music", Storefront: "de", Manufacturer: "Fender", Model: "Player Stratocaster LH", Price: 779, Currency: "EUR"}
+		repo := seed(t, newRepository, uk, de)
+
+		count, err := repo.Count(retailer.Filter{Retailer: "Gear4music"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		p, err := repo.Get(uk.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, "uk", p.Storefront)
+		assert.Equal(t, "GBP", p.Currency)
+	})
+
+	t.Run("keep listings of the same model apart", func(t *testing.T) {
+		first := retailer.Product{Retailer: "Reverb", ListingID: "61254871", Manufacturer: "Fender", Model: "Jazzmaster LH", Price: 1450}
+		second := retailer.Product{Retailer: "Reverb", ListingID: "60113402", Manufacturer: "Fender", Model: "Jazzmaster LH", Price: 1390}
+		repo := seed(t, newRepository, first, second)
+
+		count, err := repo.Count(retailer.Filter{Retailer: "Reverb"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		p, err := repo.Get(second.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, "60113402", p.ListingID)
+		assert.Equal(t, float64(1390), p.Price)
+	})
+}
+
+func testGet(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("return product by id", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		p, err := repo.Get(sgStandard.ID())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "Musik Produktiv", p.Retailer)
+		assert.Equal(t, "SG Standard Alpine White LH", p.Model)
+	})
+
+	t.Run("return error for unknown product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		_, err := repo.Get(sgStandard.ID())
+		assert.ErrorIs(t, err, retailer.ErrProductNotFound)
+	})
+}
+
+func testHistory(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("record price changes", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		reduced := jazzmasterMYS
+		reduced.Price = 1699
+		soldOut := reduced
+		soldOut.IsAvailable = false
+		reduced.IsAvailable = true
+		for _, p := range []retailer.Product{reduced, reduced, soldOut} {
+			assert.NoError(t, repo.Upsert([]retailer.Product{p}))
+		}
+
+		history, err := repo.History(jazzmasterMYS.ID())
+		assert.NoError(t, err)
+
+		assert.Len(t, history, 3)
+		assert.Equal(t, float64(1819), history[0].Price)
+		assert.Equal(t, float64(1699), history[1].Price)
+		assert.True(t, history[1].IsAvailable)
+		assert.False(t, history[2].IsAvailable)
+		assert.False(t, history[0].RecordedAt.IsZero())
+	})
+
+	t.Run("return error for unknown product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		_, err := repo.History(sgStandard.ID())
+		assert.ErrorIs(t, err, retailer.ErrProductNotFound)
+	})
+}
+
+func testDelete(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("delete product and its history", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS, sgStandard)
+
+		assert.NoError(t, repo.Delete(jazzmasterMYS.ID()))
+
+		_, err := repo.Get(jazzmasterMYS.ID())
+		assert.ErrorIs(t, err, retailer.ErrProductNotFound)
+		_, err = repo.History(jazzmasterMYS.ID())
+		assert.ErrorIs(t, err, retailer.ErrProductNotFound)
+		count, err := repo.Count(retailer.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("return error for unknown product", func(t *testing.T) {
+		repo := seed(t, newRepository, jazzmasterMYS)
+
+		err := repo.Delete(sgStandard.ID())
+		assert.ErrorIs(t, err, retailer.ErrProductNotFound)
+	})
+}
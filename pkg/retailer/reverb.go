@@ -0,0 +1,202 @@
+package retailer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const ReverbAPI = "https://api.reverb.com/api"
+
+type Reverb struct {
+	http    httpGetter
+	baseURL string
+}
+
+func NewReverb(http httpGetter, baseURL string) Reverb {
+	if baseURL == "" {
+		baseURL = ReverbAPI
+	}
+
+	return Reverb{http: http, baseURL: baseURL}
+}
+
+func (r Reverb) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	resp, err := r.http.Get(r.PageURL(category, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch listings from reverb.com: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProductResponse{}, fmt.Errorf("could not fetch listings from reverb.com: status %d", resp.StatusCode)
+	}
+
+	var p reverbPage
+	err = json.NewDecoder(resp.Body).Decode(&p)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	lastPage := p.lastPage()
+	if lastPage < options.Page {
+		return ProductResponse{}, fmt.Errorf("page %d out of bounds, last page is %d", options.Page, lastPage)
+	}
+
+	return ProductResponse{
+		Products:    p.products(r.Name()),
+		CurrentPage: p.CurrentPage,
+		LastPage:    lastPage,
+	}, nil
+}
+
+func (r Reverb) Name() string {
+	return "Reverb"
+}
+
+func (r Reverb) Categories() []string {
+	return []string{
+		"electric-guitars",
+		"acoustic-guitars",
+		"bass-guitars",
+	}
+}
+
+func (r Reverb) PageURL(category string, options RequestOptions) string {
+	var perPage uint = 50
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	query := url.Values{}
+	query.Set("handedness", "left-handed")
+	query.Set("product_type", category)
+	query.Set("per_page", strconv.Itoa(int(perPage)))
+	query.Set("page", strconv.Itoa(int(page)))
+
+	return fmt.Sprintf("%s/listings/all?%s", r.baseURL, query.Encode())
+}
+
+type reverbPage struct {
+	CurrentPage uint            `json:"current_page"`
+	TotalPages  uint            `json:"total_pages"`
+	Links       reverbLinks     `json:"_links"`
+	Listings    []reverbListing `json:"listings"`
+}
+
+func (p reverbPage) lastPage() uint {
+	lastPage := p.TotalPages
+	if p.Links.Next.Href != "" && lastPage <= p.CurrentPage {
+		lastPage = p.CurrentPage + 1
+	}
+	if lastPage < p.CurrentPage {
+		lastPage = p.CurrentPage
+	}
+
+	return lastPage
+}
+
+func (p reverbPage) products(retailer string) []Product {
+	prds := make([]Product, 0, len(p.Listings))
+	for _, l := range p.Listings {
+		if !l.isLeftHanded() {
+			continue
+		}
+
+		price, err := strconv.ParseFloat(l.Price.Amount, 64)
+		if err != nil {
+			price = 0.00
+		}
+
+		category := ""
+		if len(l.Categories) > 0 {
+			category = l.Categories[0].FullName
+		}
+
+		manufacturer, model := splitProductName(l.Title, l.Make)
+		isAvailable := l.State.Slug == "live" && l.Inventory > 0
+
+		prds = append(prds, Product{
+			Retailer:          retailer,
+			ListingID:         strconv.FormatInt(l.ID, 10),
+			Manufacturer:      manufacturer,
+			Model:             model,
+			Category:          category,
+			Condition:         l.Condition.condition(),
+			IsAvailable:       isAvailable,
+			AvailabilityInfo:  l.Condition.DisplayName,
+			AvailabilityScore: l.availabilityScore(isAvailable),
+			Price:             price,
+			Currency:          l.Price.Currency,
+			ProductURL:        l.Links.Web.Href,
+			ThumbnailURL:      l.Links.Photo.Href,
+		})
+	}
+
+	return prds
+}
+
+type reverbListing struct {
+	ID         int64            `json:"id"`
+	Make       string           `json:"make"`
+	Title      string           `json:"title"`
+	Condition  reverbCondition  `json:"condition"`
+	Price      reverbPrice      `json:"price"`
+	Inventory  int              `json:"inventory"`
+	Handedness string           `json:"handedness"`
+	State      reverbState      `json:"state"`
+	Categories []reverbCategory `json:"categories"`
+	Links      reverbLinks      `json:"_links"`
+}
+
+func (l reverbListing) isLeftHanded() bool {
+	return l.Handedness == "left-handed" || IsLeftHanded(l.Title)
+}
+
+func (l reverbListing) availabilityScore(isAvailable bool) int {
+	if isAvailable {
+		return AvailabilityAvailable
+	}
+
+	return AvailabilityUnknown
+}
+
+type reverbCondition struct {
+	DisplayName string `json:"display_name"`
+	Slug        string `json:"slug"`
+}
+
+func (c reverbCondition) condition() string {
+	if c.Slug == "brand-new" {
+		return ConditionNew
+	}
+
+	return ConditionUsed
+}
+
+type reverbPrice struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type reverbState struct {
+	Slug string `json:"slug"`
+}
+
+type reverbCategory struct {
+	FullName string `json:"full_name"`
+}
+
+type reverbLinks struct {
+	Next  reverbLink `json:"next"`
+	Web   reverbLink `json:"web"`
+	Photo reverbLink `json:"photo"`
+}
+
+type reverbLink struct {
+	Href string `json:"href"`
+}
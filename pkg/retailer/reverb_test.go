@@ -0,0 +1,180 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestReverb_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	server := newTestReverbServer()
+	t.Cleanup(server.Close)
+
+	t.Run("parse all listings on a page", func(t *testing.T) {
+		t.Parallel()
+
+		rev := NewReverb(server.Client(), server.URL)
+		response, err := rev.LoadProducts("electric-guitars", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, "Reverb", prds[0].Retailer)
+		assert.Equal(t, "61254871", prds[0].ListingID)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "American Professional II Stratocaster Left-Handed 2021 Dark Night", prds[0].Model)
+		assert.Equal(t, "Electric Guitars / Solid Body", prds[0].Category)
+		assert.Equal(t, ConditionUsed, prds[0].Condition)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, "Excellent", prds[0].AvailabilityInfo)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, float64(1450), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, "https://reverb.com/item/61254871-fender-american-professional-ii-stratocaster-left-handed-2021-dark-night", prds[0].ProductURL)
+		assert.Equal(t, "https://rvb-img.reverb.com/image/upload/s--8Kd5mJ2h--/a_0/t_card-square/v1670000000/ab12cd34.jpg", prds[0].ThumbnailURL)
+
+		assert.Equal(t, "Gibson", prds[1].Manufacturer)
+		assert.Equal(t, false, prds[1].IsAvailable)
+		assert.Equal(t, AvailabilityUnknown, prds[1].AvailabilityScore)
+		assert.Equal(t, "USD", prds[1].Currency)
+	})
+
+	t.Run("mark brand new listings as new", func(t *testing.T) {
+		t.Parallel()
+
+		rev := NewReverb(server.Client(), server.URL)
+		response, err := rev.LoadProducts("electric-guitars", RequestOptions{Page: 2})
+		assert.NoError(t, err)
+
+		assert.Len(t, response.Products, 1)
+		assert.Equal(t, ConditionNew, response.Products[0].Condition)
+	})
+
+	t.Run("parse pagination", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name                string
+			Page                uint
+			ExpectedCurrentPage uint
+			ExpectedLastPage    uint
+		}{
+			{Name: "first page", Page: 1, ExpectedCurrentPage: 1, ExpectedLastPage: 2},
+			{Name: "last page", Page: 2, ExpectedCurrentPage: 2, ExpectedLastPage: 2},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				rev := NewReverb(server.Client(), server.URL)
+				response, err := rev.LoadProducts("electric-guitars", RequestOptions{Page: tt.Page})
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.ExpectedCurrentPage, response.CurrentPage)
+				assert.Equal(t, tt.ExpectedLastPage, response.LastPage)
+			})
+		}
+	})
+
+	t.Run("follow the next link when the total page count is missing", func(t *testing.T) {
+		t.Parallel()
+
+		p := reverbPage{CurrentPage: 3, Links: reverbLinks{Next: reverbLink{Href: "https://api.reverb.com/api/listings/all?page=4"}}}
+		assert.Equal(t, uint(4), p.lastPage())
+
+		p = reverbPage{CurrentPage: 4}
+		assert.Equal(t, uint(4), p.lastPage())
+	})
+
+	t.Run("load all pages of a category", func(t *testing.T) {
+		t.Parallel()
+
+		rev := NewReverb(server.Client(), server.URL)
+		prds, err := loadProductsFromCategory(rev, "electric-guitars")
+		assert.NoError(t, err)
+
+		assert.Len(t, prds, 3)
+		assert.Equal(t, "Squier", prds[2].Manufacturer)
+	})
+
+	t.Run("request left-handed listings of the category", func(t *testing.T) {
+		t.Parallel()
+
+		httpSpy := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return http.Get(server.URL + "/listings/all?page=1")
+		}}
+		rev := NewReverb(httpSpy, "")
+
+		_, _ = rev.LoadProducts("bass-guitars", RequestOptions{})
+
+		assert.Equal(t, "https://api.reverb.com/api/listings/all?handedness=left-handed&page=1&per_page=50&product_type=bass-guitars", httpSpy.lastURL)
+	})
+
+	t.Run("drop listings that are not left-handed", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"current_page": 1, "total_pages": 1, "listings": [
+				{"id": 1, "make": "Fender", "title": "Fender Player Stratocaster 2020 Black"},
+				{"id": 2, "make": "Gibson", "title": "Gibson SG Standard 2019 Heritage Cherry", "handedness": "left-handed"},
+				{"id": 3, "make": "Ibanez", "title": "Ibanez RG470L Lefty 2005 Black"}
+			]}`))}, nil
+		}}
+		rev := NewReverb(httpStub, "")
+
+		response, err := rev.LoadProducts("electric-guitars", RequestOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Products, 2)
+		assert.Equal(t, "2", response.Products[0].ListingID)
+		assert.Equal(t, "3", response.Products[1].ListingID)
+	})
+
+	t.Run("return error on unexpected status code", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Body: ioutil.NopCloser(strings.NewReader(`{"message": "rate limited"}`))}, nil
+		}}
+		rev := NewReverb(httpStub, "")
+
+		_, err := rev.LoadProducts("electric-guitars", RequestOptions{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("return error when page is out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		rev := NewReverb(server.Client(), server.URL)
+		_, err := rev.LoadProducts("electric-guitars", RequestOptions{Page: 1337})
+
+		assert.Error(t, err)
+	})
+}
+
+func newTestReverbServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listings/all" {
+			http.NotFound(w, r)
+			return
+		}
+
+		fixture := "reverb_electric_guitars_first_page.json"
+		if r.URL.Query().Get("page") != "1" {
+			fixture = "reverb_electric_guitars_last_page.json"
+		}
+
+		w.Header().Set("content-type", "application/hal+json")
+		http.ServeFile(w, r, path.Join("testdata", fixture))
+	}))
+}
@@ -0,0 +1,222 @@
+package retailer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type ShopifyConfig struct {
+	Name        string   `json:"name"`
+	BaseURL     string   `json:"base_url"`
+	Tag         string   `json:"tag"`
+	Keyword     string   `json:"keyword"`
+	Currency    string   `json:"currency"`
+	Collections []string `json:"collections"`
+	PageSize    uint     `json:"page_size"`
+}
+
+func LoadShopifyConfigs(r io.Reader) ([]ShopifyConfig, error) {
+	configs := make([]ShopifyConfig, 0)
+
+	dec := json.NewDecoder(r)
+	for {
+		var c ShopifyConfig
+		err := dec.Decode(&c)
+		if errors.Is(err, io.EOF) {
+			return configs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode shopify config: %w", err)
+		}
+
+		configs = append(configs, c)
+	}
+}
+
+type ShopifyRetailer struct {
+	http   httpGetter
+	config ShopifyConfig
+}
+
+func NewShopifyRetailer(http httpGetter, config ShopifyConfig) (ShopifyRetailer, error) {
+	if config.Name == "" || config.BaseURL == "" {
+		return ShopifyRetailer{}, errors.New("shopify config needs a name and a base url")
+	}
+
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if len(config.Collections) == 0 {
+		config.Collections = []string{"all"}
+	}
+	if config.PageSize == 0 {
+		config.PageSize = 250
+	}
+	if config.Currency == "" {
+		config.Currency = "EUR"
+	}
+
+	return ShopifyRetailer{http: http, config: config}, nil
+}
+
+func (s ShopifyRetailer) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
+	resp, err := s.http.Get(s.PageURL(category, options))
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("could not fetch products from %s: %w", s.config.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	var p shopifyPage
+	err = json.NewDecoder(resp.Body).Decode(&p)
+	if err != nil {
+		return ProductResponse{}, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	var page uint = 1
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	lastPage := page
+	if uint(len(p.Products)) >= s.config.PageSize {
+		lastPage = page + 1
+	}
+
+	prds := make([]Product, 0)
+	for _, sp := range p.Products {
+		if s.matchesFilter(sp) {
+			prds = append(prds, s.products(sp)...)
+		}
+	}
+
+	return ProductResponse{Products: prds, CurrentPage: page, LastPage: lastPage}, nil
+}
+
+func (s ShopifyRetailer) Name() string {
+	return s.config.Name
+}
+
+func (s ShopifyRetailer) Categories() []string {
+	return s.config.Collections
+}
+
+func (s ShopifyRetailer) PageURL(category string, options RequestOptions) string {
+	var page uint = 1
+
+	if options.Page > 0 {
+		page = options.Page
+	}
+
+	return fmt.Sprintf("%s/collections/%s/products.json?limit=%d&page=%d", s.config.BaseURL, category, s.config.PageSize, page)
+}
+
+func (s ShopifyRetailer) matchesFilter(p shopifyProduct) bool {
+	if s.config.Tag == "" && s.config.Keyword == "" {
+		return IsLeftHanded(p.Title + " " + strings.Join(p.Tags, " "))
+	}
+
+	if s.config.Tag != "" {
+		for _, t := range p.Tags {
+			if strings.EqualFold(t, s.config.Tag) {
+				return true
+			}
+		}
+	}
+
+	return s.config.Keyword != "" && strings.Contains(strings.ToLower(p.Title), strings.ToLower(s.config.Keyword))
+}
+
+func (s ShopifyRetailer) products(p shopifyProduct) []Product {
+	manufacturer, model := splitProductName(p.Title, p.Vendor)
+
+	thumbnailURL := ""
+	if len(p.Images) > 0 {
+		thumbnailURL = p.Images[0].Src
+	}
+
+	prds := make([]Product, len(p.Variants))
+	for k, v := range p.Variants {
+		price, err := strconv.ParseFloat(v.Price, 64)
+		if err != nil {
+			price = 0.00
+		}
+
+		variantModel := model
+		productURL := fmt.Sprintf("%s/products/%s", s.config.BaseURL, p.Handle)
+		if v.Title != "Default Title" {
+			variantModel = fmt.Sprintf("%s %s", model, v.Title)
+			productURL = fmt.Sprintf("%s?variant=%d", productURL, v.ID)
+		}
+
+		availabilityScore := AvailabilityUnknown
+		if v.Available {
+			availabilityScore = AvailabilityAvailable
+		}
+
+		prds[k] = Product{
+			Retailer:          s.config.Name,
+			Manufacturer:      manufacturer,
+			Model:             variantModel,
+			Category:          p.ProductType,
+			IsAvailable:       v.Available,
+			AvailabilityScore: availabilityScore,
+			Price:             price,
+			Currency:          s.config.Currency,
+			ProductURL:        productURL,
+			ThumbnailURL:      thumbnailURL,
+		}
+	}
+
+	return prds
+}
+
+type shopifyPage struct {
+	Products []shopifyProduct `json:"products"`
+}
+
+type shopifyProduct struct {
+	Title       string           `json:"title"`
+	Handle      string           `json:"handle"`
+	Vendor      string           `json:"vendor"`
+	ProductType string           `json:"product_type"`
+	Tags        shopifyTags      `json:"tags"`
+	Variants    []shopifyVariant `json:"variants"`
+	Images      []shopifyImage   `json:"images"`
+}
+
+type shopifyTags []string
+
+func (t *shopifyTags) UnmarshalJSON(data []byte) error {
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err == nil {
+		*t = tags
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*t = make([]string, 0)
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+
+	return nil
+}
+
+type shopifyVariant struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Price     string `json:"price"`
+	Available bool   `json:"available"`
+}
+
+type shopifyImage struct {
+	Src string `json:"src"`
+}
@@ -0,0 +1,166 @@
+package retailer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestShopifyRetailer_LoadProducts(t *testing.T) {
+	t.Parallel()
+
+	server := newTestShopifyServer()
+	t.Cleanup(server.Close)
+
+	t.Run("map products and variants of left-handed products", func(t *testing.T) {
+		t.Parallel()
+
+		shop, err := NewShopifyRetailer(server.Client(), ShopifyConfig{Name: "Luthier Works", BaseURL: server.URL, PageSize: 3})
+		assert.NoError(t, err)
+
+		response, err := shop.LoadProducts("all", RequestOptions{})
+		assert.NoError(t, err)
+
+		prds := response.Products
+
+		assert.Len(t, prds, 3)
+		assert.Equal(t, "Luthier Works", prds[0].Retailer)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "American Vintage II 1961 Stratocaster Left-Hand, Olympic White", prds[0].Model)
+		assert.Equal(t, "Electric Guitar", prds[0].Category)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, float64(2449), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, server.URL+"/products/fender-american-vintage-ii-1961-stratocaster-lh-olympic-white", prds[0].ProductURL)
+		assert.Equal(t, "https://cdn.shopify.com/s/files/1/0123/4567/products/av2-61-strat-lh.jpg?v=1670000000", prds[0].ThumbnailURL)
+
+		assert.Equal(t, "Luthier Works", prds[1].Manufacturer)
+		assert.Equal(t, "Boutique T-Style LH Butterscotch", prds[1].Model)
+		assert.Equal(t, server.URL+"/products/boutique-t-style-lh?variant=41012345678911", prds[1].ProductURL)
+		assert.Equal(t, "Boutique T-Style LH Black", prds[2].Model)
+		assert.Equal(t, float64(3150), prds[2].Price)
+		assert.Equal(t, false, prds[2].IsAvailable)
+		assert.Equal(t, AvailabilityUnknown, prds[2].AvailabilityScore)
+	})
+
+	t.Run("filter products by tag or keyword", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			Name           string
+			Tag            string
+			Keyword        string
+			ExpectedModels []string
+		}{
+			{Name: "tag", Tag: "Left-Handed", ExpectedModels: []string{"American Vintage II 1961 Stratocaster Left-Hand, Olympic White"}},
+			{Name: "tag from comma separated tags", Tag: "lefty", ExpectedModels: []string{"Boutique T-Style LH Butterscotch", "Boutique T-Style LH Black"}},
+			{Name: "keyword", Keyword: "les paul", ExpectedModels: []string{"Les Paul Standard 60s, Bourbon Burst"}},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.Name, func(t *testing.T) {
+				t.Parallel()
+
+				shop, err := NewShopifyRetailer(server.Client(), ShopifyConfig{Name: "Shop", BaseURL: server.URL, Tag: tt.Tag, Keyword: tt.Keyword, PageSize: 3})
+				assert.NoError(t, err)
+
+				response, err := shop.LoadProducts("all", RequestOptions{})
+				assert.NoError(t, err)
+
+				models := make([]string, len(response.Products))
+				for i, p := range response.Products {
+					models[i] = p.Model
+				}
+				assert.Equal(t, tt.ExpectedModels, models)
+			})
+		}
+	})
+
+	t.Run("assume another page as long as pages are full", func(t *testing.T) {
+		t.Parallel()
+
+		shop, err := NewShopifyRetailer(server.Client(), ShopifyConfig{Name: "Shop", BaseURL: server.URL, PageSize: 3})
+		assert.NoError(t, err)
+
+		response, err := shop.LoadProducts("all", RequestOptions{Page: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), response.CurrentPage)
+		assert.Equal(t, uint(2), response.LastPage)
+
+		response, err = shop.LoadProducts("all", RequestOptions{Page: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), response.CurrentPage)
+		assert.Equal(t, uint(2), response.LastPage)
+	})
+
+	t.Run("load all pages of a collection", func(t *testing.T) {
+		t.Parallel()
+
+		shop, err := NewShopifyRetailer(server.Client(), ShopifyConfig{Name: "Shop", BaseURL: server.URL, PageSize: 3})
+		assert.NoError(t, err)
+
+		prds, err := loadProductsFromCategory(shop, "all")
+		assert.NoError(t, err)
+
+		assert.Len(t, prds, 4)
+		assert.Equal(t, "Offset Bass Left-Handed", prds[3].Model)
+	})
+
+	t.Run("request products.json of the collection", func(t *testing.T) {
+		t.Parallel()
+
+		httpSpy := &testHTTPClient{getFunc: func(url string) (*http.Response, error) {
+			return http.Get(server.URL + "/collections/all/products.json?page=2")
+		}}
+		shop, err := NewShopifyRetailer(httpSpy, ShopifyConfig{Name: "Shop", BaseURL: "https://shop.example.com/", Collections: []string{"guitars"}})
+		assert.NoError(t, err)
+
+		_, _ = shop.LoadProducts(shop.Categories()[0], RequestOptions{Page: 2})
+
+		assert.Equal(t, "https://shop.example.com/collections/guitars/products.json?limit=250&page=2", httpSpy.lastURL)
+	})
+}
+
+func TestNewShopifyRetailer(t *testing.T) {
+	_, err := NewShopifyRetailer(nil, ShopifyConfig{BaseURL: "https://shop.example.com"})
+	assert.Error(t, err)
+
+	_, err = NewShopifyRetailer(nil, ShopifyConfig{Name: "Shop"})
+	assert.Error(t, err)
+}
+
+func TestLoadShopifyConfigs(t *testing.T) {
+	configs, err := LoadShopifyConfigs(strings.NewReader(`{"name": "Luthier Works", "base_url": "https://luthier.example.com", "tag": "left-handed"}
+{"name": "Boutique Guitars", "base_url": "https://boutique.example.com", "keyword": "lefty", "currency": "GBP"}
+`))
+	assert.NoError(t, err)
+
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "Luthier Works", configs[0].Name)
+	assert.Equal(t, "left-handed", configs[0].Tag)
+	assert.Equal(t, "GBP", configs[1].Currency)
+
+	_, err = LoadShopifyConfigs(strings.NewReader(`{"name": `))
+	assert.Error(t, err)
+}
+
+func newTestShopifyServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/all/products.json" {
+			http.NotFound(w, r)
+			return
+		}
+
+		fixture := "shopify_products_first_page.json"
+		if r.URL.Query().Get("page") != "1" {
+			fixture = "shopify_products_last_page.json"
+		}
+
+		http.ServeFile(w, r, path.Join("testdata", fixture))
+	}))
+}
@@ -0,0 +1,213 @@
+package retailer
+
+import (
+	"encoding/json"
+	"github.com/PuerkitoBio/goquery"
+	"strconv"
+	"strings"
+)
+
+func ExtractProducts(doc *goquery.Document) []Product {
+	prds := make([]Product, 0)
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return
+		}
+
+		prds = append(prds, ldProducts(data)...)
+	})
+
+	doc.Find(`[itemscope][itemtype$="schema.org/Product"]`).Each(func(i int, s *goquery.Selection) {
+		prds = append(prds, microdataProduct(s))
+	})
+
+	return prds
+}
+
+func ExtractProduct(s *goquery.Selection) (Product, bool) {
+	node := s.Filter(`[itemscope][itemtype$="schema.org/Product"]`)
+	if node.Length() == 0 {
+		node = s.Find(`[itemscope][itemtype$="schema.org/Product"]`).First()
+	}
+	if node.Length() > 0 {
+		return microdataProduct(node), true
+	}
+
+	var prds []Product
+	s.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, script *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(script.Text()), &data); err == nil {
+			prds = ldProducts(data)
+		}
+		return len(prds) == 0
+	})
+	if len(prds) == 0 {
+		return Product{}, false
+	}
+
+	return prds[0], true
+}
+
+func ldProducts(data interface{}) []Product {
+	prds := make([]Product, 0)
+
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			prds = append(prds, ldProducts(item)...)
+		}
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			prds = append(prds, ldProducts(graph)...)
+		}
+
+		switch {
+		case ldHasType(v, "Product"):
+			prds = append(prds, ldProduct(v))
+		case ldHasType(v, "ItemList"):
+			prds = append(prds, ldProducts(v["itemListElement"])...)
+		case ldHasType(v, "ListItem"):
+			prds = append(prds, ldProducts(v["item"])...)
+		}
+	}
+
+	return prds
+}
+
+func ldProduct(v map[string]interface{}) Product {
+	p := Product{
+		ProductURL:   ldString(v["url"]),
+		ThumbnailURL: ldString(v["image"]),
+		GTIN:         firstNonEmpty(ldString(v["gtin13"]), ldString(v["gtin"]), ldString(v["gtin14"]), ldString(v["gtin12"]), ldString(v["gtin8"])),
+	}
+	p.Manufacturer, p.Model = splitProductName(ldString(v["name"]), ldString(v["brand"]))
+
+	offer := v["offers"]
+	if offers, ok := offer.([]interface{}); ok && len(offers) > 0 {
+		offer = offers[0]
+	}
+	if o, ok := offer.(map[string]interface{}); ok {
+		p.Price = ldPrice(o["price"])
+		if p.Price == 0 {
+			p.Price = ldPrice(o["lowPrice"])
+		}
+		p.Currency = ldString(o["priceCurrency"])
+		p.IsAvailable, p.AvailabilityScore = structuredAvailability(ldString(o["availability"]))
+		if p.ProductURL == "" {
+			p.ProductURL = ldString(o["url"])
+		}
+	}
+
+	return p
+}
+
+func ldHasType(v map[string]interface{}, t string) bool {
+	switch types := v["@type"].(type) {
+	case string:
+		return types == t
+	case []interface{}:
+		for _, tt := range types {
+			if tt == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func ldString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return strings.TrimSpace(s)
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case []interface{}:
+		if len(s) > 0 {
+			return ldString(s[0])
+		}
+	case map[string]interface{}:
+		return firstNonEmpty(ldString(s["name"]), ldString(s["url"]), ldString(s["@id"]))
+	}
+
+	return ""
+}
+
+func microdataProduct(s *goquery.Selection) Product {
+	brand := s.Find(`[itemprop="brand"]`).First()
+	brandName := microdataValue(brand)
+	if _, ok := brand.Attr("itemscope"); ok {
+		brandName = microdataValue(brand.Find(`[itemprop="name"]`).First())
+	}
+
+	offer := s.Find(`[itemprop="offers"]`).First()
+	p := Product{
+		ProductURL:   microdataValue(s.Find(`[itemprop="url"]`).First()),
+		ThumbnailURL: microdataValue(s.Find(`[itemprop="image"]`).First()),
+		GTIN:         microdataValue(s.Find(`[itemprop^="gtin"]`).First()),
+		Price:        parseLocalizedPrice(microdataValue(offer.Find(`[itemprop="price"]`).First())),
+		Currency:     microdataValue(offer.Find(`[itemprop="priceCurrency"]`).First()),
+	}
+	p.IsAvailable, p.AvailabilityScore = structuredAvailability(microdataValue(offer.Find(`[itemprop="availability"]`).First()))
+
+	name := s.Find(`[itemprop="name"]`).FilterFunction(func(i int, n *goquery.Selection) bool {
+		return n.ParentsFiltered("[itemscope]").First().IsSelection(s)
+	}).First()
+	p.Manufacturer, p.Model = splitProductName(microdataValue(name), brandName)
+
+	return p
+}
+
+func microdataValue(s *goquery.Selection) string {
+	for _, attr := range []string{"content", "href", "src"} {
+		if v, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return strings.TrimSpace(s.Text())
+}
+
+func ldPrice(v interface{}) float64 {
+	if f, ok := v.(float64); ok {
+		return f
+	}
+
+	return parseLocalizedPrice(ldString(v))
+}
+
+func splitProductName(name, brand string) (manufacturer, model string) {
+	if brand != "" {
+		return brand, strings.TrimSpace(strings.TrimPrefix(name, brand))
+	}
+
+	parts := strings.SplitN(name, " ", 2)
+	if len(parts) < 2 {
+		return "", name
+	}
+
+	return parts[0], parts[1]
+}
+
+func structuredAvailability(availability string) (isAvailable bool, score int) {
+	switch availability[strings.LastIndex(availability, "/")+1:] {
+	case "InStock", "InStoreOnly", "OnlineOnly", "LimitedAvailability":
+		return true, AvailabilityAvailable
+	case "PreOrder", "PreSale", "BackOrder":
+		return false, AvailabilityWithinWeeks
+	default:
+		return false, AvailabilityUnknown
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
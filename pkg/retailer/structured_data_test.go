@@ -0,0 +1,199 @@
+package retailer
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestExtractProducts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("extract product from json-ld", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`<script type="application/ld+json">
+			{
+				"@context": "https://schema.org",
+				"@type": "Product",
+				"name": "Fender AM Pro II Jazzmaster LH MN MYS",
+				"brand": {"@type": "Brand", "name": "Fender"},
+				"gtin13": "0885978749180",
+				"image": ["https://example.com/jazzmaster.jpg"],
+				"url": "https://example.com/jazzmaster.html",
+				"offers": {
+					"@type": "Offer",
+					"price": 1819.00,
+					"priceCurrency": "EUR",
+					"availability": "https://schema.org/InStock"
+				}
+			}
+		</script>`)
+
+		prds := ExtractProducts(doc)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, "Fender", prds[0].Manufacturer)
+		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", prds[0].Model)
+		assert.Equal(t, "0885978749180", prds[0].GTIN)
+		assert.Equal(t, float64(1819), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, true, prds[0].IsAvailable)
+		assert.Equal(t, AvailabilityAvailable, prds[0].AvailabilityScore)
+		assert.Equal(t, "https://example.com/jazzmaster.html", prds[0].ProductURL)
+		assert.Equal(t, "https://example.com/jazzmaster.jpg", prds[0].ThumbnailURL)
+	})
+
+	t.Run("extract products from json-ld item lists and graphs", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`
+			<script type="application/ld+json">
+				{"@context": "https://schema.org", "@graph": [
+					{"@type": "WebSite", "url": "https://example.com"},
+					{"@type": "ItemList", "itemListElement": [
+						{"@type": "ListItem", "position": 1, "item": {"@type": "Product", "name": "Epiphone SG Standard Alpine White LH", "offers": {"@type": "AggregateOffer", "lowPrice": "449", "priceCurrency": "EUR"}}},
+						{"@type": "ListItem", "position": 2, "item": {"@type": "Product", "name": "Gretsch G2622LH Strml. DC CB Gunmetal", "brand": "Gretsch", "offers": [{"@type": "Offer", "price": "555.00", "availability": "BackOrder"}]}}
+					]}
+				]}
+			</script>`)
+
+		prds := ExtractProducts(doc)
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, "Epiphone", prds[0].Manufacturer)
+		assert.Equal(t, "SG Standard Alpine White LH", prds[0].Model)
+		assert.Equal(t, float64(449), prds[0].Price)
+		assert.Equal(t, "Gretsch", prds[1].Manufacturer)
+		assert.Equal(t, "G2622LH Strml. DC CB Gunmetal", prds[1].Model)
+		assert.Equal(t, float64(555), prds[1].Price)
+		assert.Equal(t, false, prds[1].IsAvailable)
+		assert.Equal(t, AvailabilityWithinWeeks, prds[1].AvailabilityScore)
+	})
+
+	t.Run("extract product from microdata", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`
+			<div itemscope itemtype="https://schema.org/Product">
+				<a itemprop="url" href="https://example.com/sg.html"><span itemprop="name">Epiphone SG Standard Alpine White LH</span></a>
+				<img itemprop="image" src="https://example.com/sg.jpg">
+				<div itemprop="brand" itemscope itemtype="https://schema.org/Brand"><span itemprop="name">Epiphone</span></div>
+				<meta itemprop="gtin13" content="0711106032116">
+				<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+					<span itemprop="price" content="449.00">449,- €</span>
+					<meta itemprop="priceCurrency" content="EUR">
+					<link itemprop="availability" href="https://schema.org/OutOfStock">
+				</div>
+			</div>`)
+
+		prds := ExtractProducts(doc)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, "Epiphone", prds[0].Manufacturer)
+		assert.Equal(t, "SG Standard Alpine White LH", prds[0].Model)
+		assert.Equal(t, "0711106032116", prds[0].GTIN)
+		assert.Equal(t, float64(449), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
+		assert.Equal(t, false, prds[0].IsAvailable)
+		assert.Equal(t, AvailabilityUnknown, prds[0].AvailabilityScore)
+		assert.Equal(t, "https://example.com/sg.html", prds[0].ProductURL)
+		assert.Equal(t, "https://example.com/sg.jpg", prds[0].ThumbnailURL)
+	})
+
+	t.Run("ignore json-ld without products", func(t *testing.T) {
+		t.Parallel()
+
+		testdata, err := ioutil.ReadFile(path.Join("testdata", "musikproduktiv_guitars_eight_strings.html"))
+		assert.NoError(t, err)
+
+		prds := ExtractProducts(newTestDocument(string(testdata)))
+
+		assert.Len(t, prds, 0)
+	})
+
+	t.Run("skip malformed json-ld", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`<script type="application/ld+json">{"@type": "BreadcrumbList",</script>
+			<script type="application/ld+json">{"@type": "Product", "name": "Fender AM Pro II Jazzmaster LH MN MYS", "gtin13": "0885978749180"}</script>`)
+
+		prds := ExtractProducts(doc)
+
+		assert.Len(t, prds, 1)
+		assert.Equal(t, "0885978749180", prds[0].GTIN)
+	})
+
+	t.Run("parse prices with thousands separators", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`<script type="application/ld+json">[
+			{"@type": "Product", "name": "Fender AM Pro II Jazzmaster LH MN MYS", "offers": {"price": "1.819,00"}},
+			{"@type": "Product", "name": "Gibson Les Paul Standard 60s LH", "offers": {"price": "2,799.00"}}
+		]</script>`)
+
+		prds := ExtractProducts(doc)
+
+		assert.Len(t, prds, 2)
+		assert.Equal(t, float64(1819), prds[0].Price)
+		assert.Equal(t, float64(2799), prds[1].Price)
+	})
+}
+
+func newTestDocument(html string) *goquery.Document {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		panic(err)
+	}
+
+	return doc
+}
+
+func TestExtractProduct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("extract product from microdata of a listing node", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`<div class="product" itemscope itemtype="http://schema.org/Product">
+			<span itemprop="name">Epiphone SG Standard Alpine White LH</span>
+			<div itemprop="offers" itemscope itemtype="http://schema.org/Offer">
+				<meta itemprop="price" content="449.00">
+				<link itemprop="availability" href="http://schema.org/PreOrder">
+			</div>
+		</div>`)
+
+		p, ok := ExtractProduct(doc.Find(".product"))
+
+		assert.True(t, ok)
+		assert.Equal(t, "Epiphone", p.Manufacturer)
+		assert.Equal(t, float64(449), p.Price)
+		assert.Equal(t, false, p.IsAvailable)
+		assert.Equal(t, AvailabilityWithinWeeks, p.AvailabilityScore)
+	})
+
+	t.Run("extract product from json-ld of a listing node", func(t *testing.T) {
+		t.Parallel()
+
+		doc := newTestDocument(`<div class="product">
+			<script type="application/ld+json">{"@type": "Product", "name": "Fender AM Pro II Jazzmaster LH MN MYS", "offers": {"price": "1819.00", "availability": "InStock"}}</script>
+		</div>`)
+
+		p, ok := ExtractProduct(doc.Find(".product"))
+
+		assert.True(t, ok)
+		assert.Equal(t, "AM Pro II Jazzmaster LH MN MYS", p.Model)
+		assert.Equal(t, true, p.IsAvailable)
+	})
+
+	t.Run("report nodes without structured data", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ExtractProduct(newTestDocument(`<div class="product">Epiphone SG</div>`).Find(".product"))
+
+		assert.False(t, ok)
+	})
+}
@@ -3,7 +3,9 @@ package retailer
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/PuerkitoBio/goquery"
 	"io/ioutil"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -18,7 +20,7 @@ func NewThomann(http httpGetter) Thomann {
 }
 
 func (t Thomann) LoadProducts(category string, options RequestOptions) (ProductResponse, error) {
-	resp, err := t.http.Get(t.buildURL(category, options))
+	resp, err := t.http.Get(t.PageURL(category, options))
 	if err != nil {
 		return ProductResponse{}, fmt.Errorf("could not fetch products from thomann.de: %w", err)
 	}
@@ -53,6 +55,48 @@ func (t Thomann) LoadProducts(category string, options RequestOptions) (ProductR
 	return productResponse, nil
 }
 
+func (t Thomann) LoadDetails(p Product) (Product, error) {
+	resp, err := t.http.Get(p.ProductURL)
+	if err != nil {
+		return Product{}, fmt.Errorf("could not fetch product details from thomann.de: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Product{}, fmt.Errorf("could not fetch product details from thomann.de: status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return Product{}, fmt.Errorf("could not create goquery document from reader: %w", err)
+	}
+
+	structured := ExtractProducts(doc)
+	if len(structured) > 0 && p.GTIN == "" {
+		p.GTIN = structured[0].GTIN
+	}
+
+	specs := make(map[string]string)
+	doc.Find(".keyfeature").Each(func(i int, s *goquery.Selection) {
+		name := s.Find(".keyfeature__label").Text()
+		value := strings.TrimSpace(s.Find(".keyfeature__value").Text())
+		if name == "" || value == "" {
+			return
+		}
+
+		specs[normalizeSpecName(name)] = value
+	})
+	if len(specs) > 0 {
+		p.Specs = specs
+	}
+
+	return p, nil
+}
+
+func (t Thomann) Name() string {
+	return "Thomann"
+}
+
 func (t Thomann) Categories() []string {
 	return []string{
 		"linkshaender_modelle.html",
@@ -64,7 +108,18 @@ func (t Thomann) Categories() []string {
 	}
 }
 
-func (t Thomann) buildURL(category string, options RequestOptions) string {
+func (t Thomann) DiscoverCategories() ([]string, error) {
+	links, err := discoverNavigationLinks(t.http, "https://www.thomann.de/de/linkshaender.html", "a[href]")
+	if err != nil {
+		return nil, err
+	}
+
+	return categorySlugs(links, "/de/", func(slug string) bool {
+		return slug != "linkshaender.html" && strings.Contains(slug, "linkshaender") && strings.HasSuffix(slug, ".html") && !strings.Contains(slug, "/")
+	}), nil
+}
+
+func (t Thomann) PageURL(category string, options RequestOptions) string {
 	var productsPerPage uint = 100
 	var page uint = 1
 
@@ -101,6 +156,7 @@ func (p page) products() []Product {
 			AvailabilityInfo:  v.Availability.Text,
 			AvailabilityScore: v.Availability.Score(),
 			Price:             price,
+			Currency:          "EUR",
 			ProductURL:        productURL,
 			ThumbnailURL:      thumbnailURL,
 		}
@@ -5,15 +5,13 @@ package retailer
 
 import (
 	"github.com/stretchr/testify/assert"
-	"net/http"
 	"testing"
-	"time"
 )
 
 func TestThomann_LoadProductsIntegration(t *testing.T) {
 	t.Parallel()
 
-	c := &http.Client{Timeout: 5 * time.Second}
+	c := newIntegrationHTTPClient(t)
 	tho := Thomann{http: c}
 
 	response, err := tho.LoadProducts(tho.Categories()[0], RequestOptions{})
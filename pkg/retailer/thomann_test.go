@@ -31,6 +31,7 @@ func TestThomann_LoadProducts(t *testing.T) {
 		assert.Equal(t, "In 4–5 Wochen lieferbar", prds[0].AvailabilityInfo)
 		assert.Equal(t, AvailabilityWithinWeeks, prds[0].AvailabilityScore)
 		assert.Equal(t, float64(599), prds[0].Price)
+		assert.Equal(t, "EUR", prds[0].Currency)
 		assert.Equal(t, "https://www.thomann.de/de/esp_ltd_b206sm_natural_satin_left_443915.htm?listPosition=0", prds[0].ProductURL)
 		assert.Equal(t, "https://thumbs.static-thomann.de/thumb/thumb220x220/pics/prod/443915.jpg", prds[0].ThumbnailURL)
 
@@ -119,6 +120,94 @@ func TestThomann_LoadProducts(t *testing.T) {
 	})
 }
 
+func TestThomann_LoadDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse specifications from product page", func(t *testing.T) {
+		t.Parallel()
+
+		tho := Thomann{newTestHTTPClientForFixture("thomann_product_details.html")}
+		p := Product{Manufacturer: "ESP", Model: "LTD B206SM Natural Satin Left", Price: 599}
+
+		p, err := tho.LoadDetails(p)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "LTD B206SM Natural Satin Left", p.Model)
+		assert.Equal(t, float64(599), p.Price)
+		assert.Equal(t, "4533940123456", p.GTIN)
+		assert.Len(t, p.Specs, 6)
+		assert.Equal(t, "864 mm", p.Specs[SpecScaleLength])
+		assert.Equal(t, "4,3 kg", p.Specs[SpecWeight])
+		assert.Equal(t, "Thin U", p.Specs[SpecNeckProfile])
+		assert.Equal(t, "ESP Designed SB-6 (H-H)", p.Specs[SpecPickups])
+		assert.Equal(t, "Nein", p.Specs[SpecCaseIncluded])
+		assert.Equal(t, "Esche", p.Specs["Korpus"])
+	})
+
+	t.Run("request the product url", func(t *testing.T) {
+		t.Parallel()
+
+		httpSpy := testHTTPClient{
+			getFunc: func(url string) (*http.Response, error) {
+				return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+			},
+		}
+		tho := Thomann{http: &httpSpy}
+
+		_, _ = tho.LoadDetails(Product{ProductURL: "https://www.thomann.de/de/esp_ltd_b206sm_natural_satin_left_443915.htm"})
+
+		assert.Equal(t, "https://www.thomann.de/de/esp_ltd_b206sm_natural_satin_left_443915.htm", httpSpy.lastURL)
+	})
+
+	t.Run("return error if the product page could not be loaded", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := testHTTPClient{
+			getFunc: func(url string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString("not found"))}, nil
+			},
+		}
+		tho := Thomann{http: &httpStub}
+
+		_, err := tho.LoadDetails(Product{ProductURL: "https://www.thomann.de/de/gone.htm"})
+		assert.Error(t, err)
+	})
+
+	t.Run("leave specifications empty if the page has none", func(t *testing.T) {
+		t.Parallel()
+
+		httpStub := testHTTPClient{
+			getFunc: func(url string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("<html><body>Cookie consent</body></html>"))}, nil
+			},
+		}
+		tho := Thomann{http: &httpStub}
+
+		p, err := tho.LoadDetails(Product{ProductURL: "https://www.thomann.de/de/consent.htm"})
+		assert.NoError(t, err)
+		assert.Nil(t, p.Specs)
+	})
+}
+
+func TestThomann_DiscoverCategories(t *testing.T) {
+	httpSpy := newTestHTTPClientForFixture("thomann_left_handed_navigation.html")
+	tho := Thomann{http: httpSpy}
+
+	categories, err := tho.DiscoverCategories()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://www.thomann.de/de/linkshaender.html", httpSpy.lastURL)
+	assert.Equal(t, []string{
+		"linkshaender_modelle.html",
+		"linkshaender_konzertgitarren.html",
+		"linkshaender_akustikgitarren.html",
+		"4_saitige_linkshaender_e-baesse.html",
+		"5_saitige_linkshaender_e-baesse.html",
+		"linkshaender_ukulelen.html",
+		"7_saitige_linkshaender_e-gitarren.html",
+	}, categories)
+}
+
 func TestAvailability_Score(t *testing.T) {
 	tests := []struct {
 		Name          string
@@ -157,7 +246,7 @@ func newTestHTTPClientForFixture(fixture string) *testHTTPClient {
 
 	return &testHTTPClient{
 		getFunc: func(url string) (*http.Response, error) {
-			return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(testdata))}, nil
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(testdata))}, nil
 		},
 	}
 }